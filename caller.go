@@ -0,0 +1,146 @@
+package plogs
+
+import (
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+const (
+	_CallerBegin   CallerMode = iota // begin
+	CallerFull                       // 绝对路径: /home/user/project/cmd/main.go:20
+	CallerRelative                   // 相对于module根目录的路径: cmd/main.go:20, 依赖包中的文件使用导入路径
+	CallerShort                      // 包所在目录/文件名: cmd/main.go:20
+	CallerNone                       // 不记录调用者, 省去获取调用栈的开销
+	_CallerEnd                       // end
+)
+
+// 从调用runtime.Callers的函数到调用Logger方法的用户代码之间的栈帧数:
+// runtime.Callers -> captureCaller -> Logger.log -> Logger.Info等 -> 用户代码
+const callerBaseSkip = 4
+
+type CallerMode int // CallerMode 调用者的输出方式
+
+func (m CallerMode) valid() bool {
+	return m > _CallerBegin && m < _CallerEnd
+}
+
+// callerInfo 同一个pc对应的调用者信息是固定的, 按pc缓存格式化后的结果
+type callerInfo struct {
+	caller EntryCaller
+}
+
+var (
+	buildOnce  sync.Once
+	mainPath   string // main包的导入路径
+	modulePath string // main module的路径
+)
+
+// WithCallerSkip 返回额外跳过skip层调用栈的子Logger, 用于在自己封装的日志函数中使用, 使记录的调用者为封装函数的调用方
+func (l *Logger) WithCallerSkip(skip int) *Logger {
+	if l == nil || skip == 0 {
+		return l
+	}
+	child := l.clone()
+	child.callerSkip += skip
+	return child
+}
+
+func (l *Logger) captureCaller(e *Entry) {
+	config := l.config
+	if config.callerMode == CallerNone {
+		return
+	}
+
+	var pcs [1]uintptr
+	if runtime.Callers(callerBaseSkip+config.callerSkip+l.callerSkip, pcs[:]) < 1 {
+		e.Caller = EntryCaller{Defined: true, File: "???"}
+		return
+	}
+	if cached, ok := l.callers.Load(pcs[0]); ok {
+		e.Caller = cached.(*callerInfo).caller
+		return
+	}
+
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	info := &callerInfo{
+		caller: EntryCaller{
+			Defined: true,
+			File:    formatCallerFile(config.callerMode, frame.File, frame.Function),
+			Line:    frame.Line,
+		},
+	}
+	if config.callerFunc {
+		info.caller.Function = shortFunction(frame.Function)
+	}
+	l.callers.Store(pcs[0], info)
+	e.Caller = info.caller
+}
+
+func formatCallerFile(mode CallerMode, file, function string) string {
+	switch mode {
+	case CallerShort:
+		return shortFile(file)
+	case CallerRelative:
+		return relativeFile(file, function)
+	default:
+		return file
+	}
+}
+
+// 保留文件所在的目录名与文件名
+func shortFile(file string) string {
+	i := strings.LastIndexByte(file, '/')
+	if i < 0 {
+		return file
+	}
+	if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+		return file[j+1:]
+	}
+	return file
+}
+
+// 根据函数名得到包的导入路径, 再去掉main module的路径前缀
+func relativeFile(file, function string) string {
+	buildOnce.Do(func() {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			mainPath = info.Path
+			modulePath = info.Main.Path
+		}
+	})
+
+	pkgPath := packagePath(function)
+	if pkgPath == "main" {
+		pkgPath = mainPath
+	}
+	switch {
+	case pkgPath == "":
+		return shortFile(file)
+	case modulePath != "" && pkgPath == modulePath:
+		return filepath.Base(file)
+	case modulePath != "" && strings.HasPrefix(pkgPath, modulePath+"/"):
+		return pkgPath[len(modulePath)+1:] + "/" + filepath.Base(file)
+	case pkgPath == mainPath:
+		// 无法确定main包所在的module(如go run xxx.go), 只保留文件名
+		return filepath.Base(file)
+	default:
+		return pkgPath + "/" + filepath.Base(file)
+	}
+}
+
+// github.com/pyihe/plogs/cmd.(*T).Run -> github.com/pyihe/plogs/cmd
+func packagePath(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	dot := strings.IndexByte(function[slash+1:], '.')
+	if dot < 0 {
+		return ""
+	}
+	return function[:slash+1+dot]
+}
+
+// github.com/pyihe/plogs/cmd.(*T).Run -> cmd.(*T).Run
+func shortFunction(function string) string {
+	return function[strings.LastIndexByte(function, '/')+1:]
+}
@@ -14,7 +14,7 @@ func main() {
 		plogs.WithFileOption(plogs.WriteByLevelMerged),
 		plogs.WithLogPath("./logs"),
 		plogs.WithStdout(true),
-		plogs.WithLogLevel(plogs.LevelInfo | plogs.LevelDebug | plogs.LevelWarn | plogs.LevelError | plogs.LevelFatal | plogs.LevelPanic),
+		plogs.WithMinLevel(plogs.LevelDebug),
 		plogs.WithMaxAge(24 * time.Hour),
 		plogs.WithMaxSize(60 * 1024 * 1024),
 	}
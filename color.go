@@ -0,0 +1,74 @@
+package plogs
+
+import (
+	"os"
+)
+
+const (
+	_ColorBegin ColorMode = iota // begin
+	ColorAuto                    // 输出到终端且没有设置NO_COLOR环境变量时才着色
+	ColorAlways                  // 总是着色
+	ColorNever                   // 不着色
+	_ColorEnd                    // end
+)
+
+type (
+	ColorMode int              // ColorMode 终端输出的着色方式
+	Palette   map[Level]string // Palette 各级别使用的ANSI SGR参数, 如"31"为红色, "1;31"为加粗的红色
+)
+
+// DefaultPalette 默认的配色
+var DefaultPalette = Palette{
+	LevelPanic: "1;35",
+	LevelFatal: "1;31",
+	LevelError: "31",
+	LevelWarn:  "33",
+	LevelInfo:  "32",
+	LevelDebug: "90",
+}
+
+func (m ColorMode) valid() bool {
+	return m > _ColorBegin && m < _ColorEnd
+}
+
+// 判断输出到file时是否需要着色
+func (m ColorMode) enabled(file *os.File) bool {
+	switch m {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(file)
+}
+
+func isTerminal(file *os.File) bool {
+	stat, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// 为日志添加颜色, 末尾的换行符放在颜色重置之后; 没有配置颜色的级别原样返回
+func (p Palette) colorize(level Level, data []byte) []byte {
+	code, ok := p[level]
+	if !ok || code == "" || len(data) == 0 {
+		return data
+	}
+	end := len(data)
+	if data[end-1] == '\n' {
+		end--
+	}
+	colored := make([]byte, 0, len(data)+len(code)+8)
+	colored = append(colored, "\x1b["...)
+	colored = append(colored, code...)
+	colored = append(colored, 'm')
+	colored = append(colored, data[:end]...)
+	colored = append(colored, "\x1b[0m"...)
+	colored = append(colored, data[end:]...)
+	return colored
+}
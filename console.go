@@ -0,0 +1,80 @@
+package plogs
+
+import (
+	"os"
+
+	"github.com/pyihe/plogs/writer"
+)
+
+const (
+	ConsoleNone   ConsoleMode = iota // 不输出到终端
+	ConsoleStdout                    // 全部输出到stdout
+	ConsoleStderr                    // 全部输出到stderr
+	ConsoleSplit                     // WithStderrLevel指定的级别输出到stderr, 其余级别输出到stdout
+	_ConsoleEnd                      // end
+)
+
+// 终端writer的名称
+const (
+	stdoutName = builtinPrefix + "stdout"
+	stderrName = builtinPrefix + "stderr"
+)
+
+type ConsoleMode int // ConsoleMode 终端输出方式
+
+func (m ConsoleMode) valid() bool {
+	return m >= ConsoleNone && m < _ConsoleEnd
+}
+
+func (l *Logger) addConsoleWriter() {
+	var config = l.config
+	var useStdout, useStderr bool
+
+	switch config.console {
+	case ConsoleStdout:
+		useStdout = true
+	case ConsoleStderr:
+		useStderr = true
+	case ConsoleSplit:
+		useStdout, useStderr = true, true
+	}
+	// 路由规则指向终端时也需要创建
+	useStdout = useStdout || l.routesTo(stdoutName)
+	useStderr = useStderr || l.routesTo(stderrName)
+	if useStdout {
+		l.writer.AddWriter(FormatWriter(writer.NewStdWriter(stdoutName, os.Stdout), config.consoleFormatter))
+		l.stdoutColor = config.colorMode.enabled(os.Stdout)
+	}
+	if useStderr {
+		l.writer.AddWriter(FormatWriter(writer.NewStdWriter(stderrName, os.Stderr), config.consoleFormatter))
+		l.stderrColor = config.colorMode.enabled(os.Stderr)
+	}
+}
+
+// 获取level级别的日志需要输出到的终端writer以及是否需要着色
+func (l *Logger) consoleOutput(level Level) (name string, color bool) {
+	var config = l.config
+	switch config.console {
+	case ConsoleStdout:
+		return stdoutName, l.stdoutColor
+	case ConsoleStderr:
+		return stderrName, l.stderrColor
+	case ConsoleSplit:
+		if config.stderrLevels&level == level {
+			return stderrName, l.stderrColor
+		}
+		return stdoutName, l.stdoutColor
+	}
+	return "", false
+}
+
+// 写入name时是否需要着色
+func (l *Logger) colorOf(name string) bool {
+	switch name {
+	case stdoutName:
+		return l.stdoutColor
+	case stderrName:
+		return l.stderrColor
+	}
+	return false
+}
@@ -19,6 +19,9 @@ const (
 	_WriteEnd                               // end
 )
 
+// 匹配所有级别, 包括创建Logger之后才注册的自定义级别
+const levelAll = ^Level(0)
+
 type (
 	Level      int // Level 日志级别
 	FileOption int // FileOption 日志文件写选项
@@ -29,45 +32,27 @@ func (w FileOption) valid() bool {
 }
 
 func (l Level) valid() bool {
-	return l > _LevelBegin && l < _LevelEnd
+	_, ok := lookupLevel(l)
+	return ok
 }
 
 // 每个级别的日志对应的prefix
-func (l Level) prefix() (prefix string) {
-	switch l {
-	case LevelPanic:
-		prefix = "[P] "
-	case LevelFatal:
-		prefix = "[F] "
-	case LevelError:
-		prefix = "[E] "
-	case LevelWarn:
-		prefix = "[W] "
-	case LevelInfo:
-		prefix = "[I] "
-	case LevelDebug:
-		prefix = "[D] "
+func (l Level) prefix() string {
+	if desc, ok := lookupLevel(l); ok {
+		return desc.prefix
 	}
-	return
+	return ""
 }
 
 // 需要区分级别存放日志信息时，用于获取每个级别日志存放的子目录
 func subPath(l Level) (suffix string) {
 	switch l {
-	case LevelPanic:
-		suffix = "panics"
-	case LevelFatal:
-		suffix = "fatals"
-	case LevelError:
-		suffix = "errors"
-	case LevelWarn:
-		suffix = "warns"
-	case LevelInfo:
-		suffix = "infos"
-	case LevelDebug:
-		suffix = "debugs"
-	case _LevelBegin:
-		suffix = "begins"
+	case _LevelEnd:
+		suffix = ""
+	default:
+		if desc, ok := lookupLevel(l); ok {
+			suffix = desc.dir
+		}
 	}
 	return
 }
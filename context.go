@@ -0,0 +1,41 @@
+package plogs
+
+import (
+	"context"
+)
+
+// ContextExtractor 从context.Context中提取需要记录的字段, 如trace id、request id等
+type ContextExtractor interface {
+	Extract(ctx context.Context) []Field
+}
+
+// ContextExtractorFunc 函数形式的ContextExtractor
+type ContextExtractorFunc func(ctx context.Context) []Field
+
+func (f ContextExtractorFunc) Extract(ctx context.Context) []Field {
+	return f(ctx)
+}
+
+// ContextValue 返回一个ContextExtractor: 如果ctx.Value(ctxKey)不为nil, 则以key为字段名记录该值
+func ContextValue(key string, ctxKey interface{}) ContextExtractor {
+	return ContextExtractorFunc(func(ctx context.Context) []Field {
+		value := ctx.Value(ctxKey)
+		if value == nil {
+			return nil
+		}
+		return []Field{Any(key, value)}
+	})
+}
+
+// 依次执行注册的extractor, 提取出的字段排在调用时传入的字段之前
+func (l *Logger) contextFields(ctx context.Context, keysAndValues []interface{}) []Field {
+	fields := sweetenFields(keysAndValues)
+	if ctx == nil || len(l.config.extractors) == 0 {
+		return fields
+	}
+	var extracted []Field
+	for _, extractor := range l.config.extractors {
+		extracted = append(extracted, extractor.Extract(ctx)...)
+	}
+	return append(extracted, fields...)
+}
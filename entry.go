@@ -0,0 +1,34 @@
+package plogs
+
+import (
+	"strconv"
+	"time"
+)
+
+// Entry 一条日志记录, 在交给Formatter编码之前始终保持结构化
+type Entry struct {
+	Time    time.Time   // 时间
+	Level   Level       // 级别
+	App     string      // 应用名, 通过WithName设置
+	Logger  string      // Logger名称, 通过Named设置
+	Caller  EntryCaller // 调用者
+	Message string      // 日志内容
+	Fields  []Field     // 结构化字段
+	Stack   string      // 调用栈, 仅Panic级别的日志记录
+}
+
+// EntryCaller 调用者信息, 其中File已经按照WithCallerMode处理
+type EntryCaller struct {
+	Defined  bool   // 是否记录了调用者
+	File     string // 文件
+	Line     int    // 行号
+	Function string // 函数名, 仅在开启WithCallerFunction时记录
+}
+
+// String 返回file:line形式的调用者
+func (c EntryCaller) String() string {
+	if !c.Defined {
+		return ""
+	}
+	return c.File + ":" + strconv.Itoa(c.Line)
+}
@@ -0,0 +1,198 @@
+package plogs
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+const (
+	_FieldBegin  FieldType = iota // begin
+	StringType                    // string
+	IntType                       // 有符号整数
+	UintType                      // 无符号整数
+	FloatType                     // 浮点数
+	BoolType                      // bool
+	DurationType                  // time.Duration
+	TimeType                      // time.Time
+	ErrorType                     // error
+	AnyType                       // 其他任意类型
+)
+
+// badKey 键值对参数中缺少key时使用的key
+const badKey = "!BADKEY"
+
+type FieldType uint8 // FieldType 字段值的类型
+
+// Field 结构化日志的键值对字段, 通过String、Int、Duration、Err等函数构造
+type Field struct {
+	Key     string    // 字段名
+	Type    FieldType // 字段值的类型
+	integer int64     // 整数、bool、浮点数(按位存储)、time.Duration
+	str     string    // 字符串
+	iface   interface{}
+}
+
+func String(key string, value string) Field {
+	return Field{Key: key, Type: StringType, str: value}
+}
+
+func Int(key string, value int) Field {
+	return Int64(key, int64(value))
+}
+
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Type: IntType, integer: value}
+}
+
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, Type: UintType, integer: int64(value)}
+}
+
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Type: FloatType, integer: int64(math.Float64bits(value))}
+}
+
+func Bool(key string, value bool) Field {
+	var n int64
+	if value {
+		n = 1
+	}
+	return Field{Key: key, Type: BoolType, integer: n}
+}
+
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Type: DurationType, integer: int64(value)}
+}
+
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Type: TimeType, iface: value}
+}
+
+// Err 以"error"为key记录错误信息
+func Err(err error) Field {
+	return NamedErr("error", err)
+}
+
+func NamedErr(key string, err error) Field {
+	return Field{Key: key, Type: ErrorType, iface: err}
+}
+
+// Any 根据value的实际类型构造字段, 无法识别的类型按AnyType处理
+func Any(key string, value interface{}) Field {
+	switch v := value.(type) {
+	case Field:
+		return v
+	case string:
+		return String(key, v)
+	case int:
+		return Int(key, v)
+	case int8:
+		return Int64(key, int64(v))
+	case int16:
+		return Int64(key, int64(v))
+	case int32:
+		return Int64(key, int64(v))
+	case int64:
+		return Int64(key, v)
+	case uint:
+		return Uint64(key, uint64(v))
+	case uint8:
+		return Uint64(key, uint64(v))
+	case uint16:
+		return Uint64(key, uint64(v))
+	case uint32:
+		return Uint64(key, uint64(v))
+	case uint64:
+		return Uint64(key, v)
+	case float32:
+		return Float64(key, float64(v))
+	case float64:
+		return Float64(key, v)
+	case bool:
+		return Bool(key, v)
+	case time.Duration:
+		return Duration(key, v)
+	case time.Time:
+		return Time(key, v)
+	case error:
+		return NamedErr(key, v)
+	default:
+		return Field{Key: key, Type: AnyType, iface: v}
+	}
+}
+
+// Value 返回字段的原始值
+func (f Field) Value() interface{} {
+	switch f.Type {
+	case StringType:
+		return f.str
+	case IntType:
+		return f.integer
+	case UintType:
+		return uint64(f.integer)
+	case FloatType:
+		return math.Float64frombits(uint64(f.integer))
+	case BoolType:
+		return f.integer == 1
+	case DurationType:
+		return time.Duration(f.integer)
+	default:
+		return f.iface
+	}
+}
+
+// 将键值对参数转换为Field: 参数可以直接是Field, 也可以是交替出现的key、value
+func sweetenFields(args []interface{}) []Field {
+	if len(args) == 0 {
+		return nil
+	}
+	fields := make([]Field, 0, len(args))
+	for i := 0; i < len(args); {
+		if f, ok := args[i].(Field); ok {
+			fields = append(fields, f)
+			i++
+			continue
+		}
+		// 最后一个参数没有对应的value
+		if i == len(args)-1 {
+			fields = append(fields, Any(badKey, args[i]))
+			break
+		}
+		key, ok := args[i].(string)
+		if !ok {
+			key = fmt.Sprint(args[i])
+		}
+		fields = append(fields, Any(key, args[i+1]))
+		i += 2
+	}
+	return fields
+}
+
+// 字段值的文本形式
+func (f Field) valueString() string {
+	switch f.Type {
+	case StringType:
+		return f.str
+	case IntType:
+		return strconv.FormatInt(f.integer, 10)
+	case UintType:
+		return strconv.FormatUint(uint64(f.integer), 10)
+	case FloatType:
+		return strconv.FormatFloat(math.Float64frombits(uint64(f.integer)), 'g', -1, 64)
+	case BoolType:
+		return strconv.FormatBool(f.integer == 1)
+	case DurationType:
+		return time.Duration(f.integer).String()
+	case TimeType:
+		return f.iface.(time.Time).Format(time.RFC3339Nano)
+	case ErrorType:
+		if f.iface == nil {
+			return "<nil>"
+		}
+		return f.iface.(error).Error()
+	default:
+		return fmt.Sprint(f.iface)
+	}
+}
@@ -0,0 +1,71 @@
+package plogs
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/pyihe/plogs/writer"
+)
+
+// FormattedWriter writer可以实现该接口声明自己使用的Formatter, 未实现或者返回nil时使用WithFormatter设置的Formatter
+type FormattedWriter interface {
+	Formatter() Formatter
+}
+
+type formattedWriter struct {
+	writer.LogWriter
+	formatter Formatter
+}
+
+// FormatWriter 为writer指定Formatter, 使不同的writer可以使用不同的编码方式
+func FormatWriter(w writer.LogWriter, f Formatter) writer.LogWriter {
+	if f == nil {
+		return w
+	}
+	return &formattedWriter{
+		LogWriter: w,
+		formatter: f,
+	}
+}
+
+func (fw *formattedWriter) Formatter() Formatter {
+	return fw.formatter
+}
+
+// 获取writer使用的Formatter
+func (l *Logger) formatterOf(w writer.LogWriter) Formatter {
+	if fw, ok := w.(FormattedWriter); ok {
+		if f := fw.Formatter(); f != nil {
+			return f
+		}
+	}
+	return l.config.formatter
+}
+
+// encodeCache 同一条日志只会被同一个Formatter编码一次, 编码结果由使用该Formatter的所有writer共享
+type encodeCache struct {
+	formatters []Formatter
+	data       [][]byte
+}
+
+func (c *encodeCache) encode(f Formatter, e *Entry) ([]byte, bool) {
+	comparable := reflect.TypeOf(f).Comparable()
+	if comparable {
+		for i, cached := range c.formatters {
+			if cached == f {
+				return c.data[i], c.data[i] != nil
+			}
+		}
+	}
+	data, err := f.Format(e)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "plogs: format entry failed: %v\n", err)
+		data = nil
+	}
+	if comparable {
+		c.formatters = append(c.formatters, f)
+		c.data = append(c.data, data)
+	}
+	return data, data != nil
+}
@@ -0,0 +1,95 @@
+package plogs
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/pyihe/go-pkg/buffers"
+	bytesx "github.com/pyihe/go-pkg/bytes"
+	"github.com/pyihe/go-pkg/times"
+)
+
+// Formatter 将Entry编码为写入writer的字节, 返回的字节需要包含换行符
+type Formatter interface {
+	Format(e *Entry) ([]byte, error)
+}
+
+// TextFormatter 默认的文本格式: [app] [L] [time] [name] file:line function message key=value ...
+type TextFormatter struct {
+	TimeFormat string // 时间格式, 默认为times.SlashWithMillFormat
+}
+
+func (f *TextFormatter) Format(e *Entry) ([]byte, error) {
+	timeFormat := f.TimeFormat
+	if timeFormat == "" {
+		timeFormat = times.SlashWithMillFormat
+	}
+
+	b := buffers.Get()
+	// write app name
+	if e.App != "" {
+		b.WriteString("[")
+		b.WriteString(e.App)
+		b.WriteString("] ")
+	}
+	// write prefix
+	b.WriteString(e.Level.prefix())
+
+	// write timedesc
+	b.WriteString("[")
+	b.WriteString(e.Time.Format(timeFormat))
+	b.WriteString("] ")
+
+	// write logger name
+	if e.Logger != "" {
+		b.WriteString("[")
+		b.WriteString(e.Logger)
+		b.WriteString("] ")
+	}
+
+	// write file
+	if e.Caller.Defined {
+		b.WriteString(e.Caller.String())
+		b.WriteString(" ")
+	}
+	if e.Caller.Function != "" {
+		b.WriteString(e.Caller.Function)
+		b.WriteString(" ")
+	}
+
+	// write message
+	b.WriteString(e.Message)
+
+	// write fields
+	for _, field := range e.Fields {
+		b.WriteString(" ")
+		b.WriteString(field.Key)
+		b.WriteString("=")
+		writeTextValue(b, field.valueString())
+	}
+
+	//new line
+	b.WriteString("\n")
+
+	// write stack
+	if e.Stack != "" {
+		b.WriteString(e.Stack)
+		if !strings.HasSuffix(e.Stack, "\n") {
+			b.WriteString("\n")
+		}
+	}
+
+	data := bytesx.Copy(b.Bytes())
+	buffers.Put(b)
+	return data, nil
+}
+
+// 字段值中含有空白、引号或等号时需要加上引号, 避免与其他字段混淆
+func writeTextValue(b *bytes.Buffer, value string) {
+	if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+		b.WriteString(strconv.Quote(value))
+		return
+	}
+	b.WriteString(value)
+}
@@ -2,4 +2,4 @@ module github.com/pyihe/plogs
 
 go 1.17
 
-require github.com/pyihe/go-pkg v0.0.0-20220816061532-b61575b24296 // indirect
+require github.com/pyihe/go-pkg v0.0.0-20220816061532-b61575b24296
@@ -0,0 +1,83 @@
+package plogs
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// levelState LevelHandler返回的日志级别信息
+type levelState struct {
+	Level Level            `json:"level"`           // 全局日志级别
+	Named map[string]Level `json:"named,omitempty"` // 按名称设置的日志级别
+}
+
+// levelRequest 修改日志级别的请求, name为空时修改全局日志级别
+type levelRequest struct {
+	Name  string `json:"name"`
+	Level *Level `json:"level"`
+}
+
+type levelHandler struct {
+	logger *Logger
+}
+
+// LevelHandler 返回用于查看与修改日志级别的http.Handler, 可以与net/http/pprof挂载在同一个管理端口上:
+//
+//	GET                                       查看当前日志级别
+//	PUT/POST {"level": "warn+"}               修改全局日志级别, 格式同ParseLevel
+//	PUT/POST {"name": "db", "level": "all"}   修改名称为db的Logger及其子Logger的日志级别
+//	DELETE   ?name=db                         删除名称为db的日志级别配置
+func LevelHandler(logger *Logger) http.Handler {
+	// 使用未命名的Logger, 保证不带name时修改的总是全局日志级别
+	return &levelHandler{
+		logger: &Logger{logCore: logger.logCore},
+	}
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		var req levelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.error(w, http.StatusBadRequest, err)
+			return
+		}
+		if req.Level == nil {
+			h.error(w, http.StatusBadRequest, errors.New("level is required"))
+			return
+		}
+		if req.Name == "" {
+			h.logger.SetLevel(*req.Level)
+		} else {
+			h.logger.SetNamedLevel(req.Name, *req.Level)
+		}
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			h.error(w, http.StatusBadRequest, errors.New("name is required"))
+			return
+		}
+		h.logger.RemoveNamedLevel(name)
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST, DELETE")
+		h.error(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	h.reply(w, http.StatusOK, levelState{
+		Level: h.logger.Level(),
+		Named: h.logger.NamedLevels(),
+	})
+}
+
+func (h *levelHandler) error(w http.ResponseWriter, code int, err error) {
+	h.reply(w, code, map[string]string{"error": err.Error()})
+}
+
+func (h *levelHandler) reply(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
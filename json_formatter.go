@@ -0,0 +1,209 @@
+package plogs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pyihe/go-pkg/buffers"
+	bytesx "github.com/pyihe/go-pkg/bytes"
+)
+
+// 时间戳以Unix时间的形式输出, 可用于JSONFormatter.TimeFormat等
+const (
+	TimeFormatEpoch       = "epoch"        // 秒, 带小数: 1660635332.123456
+	TimeFormatEpochMillis = "epoch_millis" // 毫秒
+	TimeFormatEpochNanos  = "epoch_nanos"  // 纳秒
+)
+
+// OmitKey 将JSONFormatter等的key设置为OmitKey时不输出对应的内容
+const OmitKey = "-"
+
+// JSONFormatter 每条日志编码为一行JSON对象, 结构化字段与ts、level等处于同一层级,
+// key为空时使用默认值, 设置为OmitKey时不输出
+type JSONFormatter struct {
+	TimeKey     string // 时间, 默认"ts"
+	LevelKey    string // 级别, 默认"level"
+	AppKey      string // 应用名, 默认"app"
+	LoggerKey   string // Logger名称, 默认"logger"
+	CallerKey   string // 调用者, 默认"caller"
+	FunctionKey string // 调用者函数名, 默认"func"
+	MessageKey  string // 日志内容, 默认"msg"
+	StackKey    string // 调用栈, 默认"stack"
+	TimeFormat  string // 时间格式, 默认time.RFC3339Nano, 也可以使用TimeFormatEpoch等输出时间戳
+}
+
+func (f *JSONFormatter) Format(e *Entry) ([]byte, error) {
+	b := buffers.Get()
+	enc := jsonEncoder{buf: b, timeFormat: f.TimeFormat}
+	b.WriteByte('{')
+
+	enc.addKey(keyOr(f.TimeKey, "ts"))
+	enc.appendTime(e.Time)
+	enc.addString(keyOr(f.LevelKey, "level"), e.Level.String())
+	if e.App != "" {
+		enc.addString(keyOr(f.AppKey, "app"), e.App)
+	}
+	if e.Logger != "" {
+		enc.addString(keyOr(f.LoggerKey, "logger"), e.Logger)
+	}
+	if e.Caller.Defined {
+		enc.addString(keyOr(f.CallerKey, "caller"), e.Caller.String())
+	}
+	if e.Caller.Function != "" {
+		enc.addString(keyOr(f.FunctionKey, "func"), e.Caller.Function)
+	}
+	enc.addString(keyOr(f.MessageKey, "msg"), e.Message)
+	for _, field := range e.Fields {
+		enc.addField(field)
+	}
+	if e.Stack != "" {
+		enc.addString(keyOr(f.StackKey, "stack"), e.Stack)
+	}
+
+	b.WriteString("}\n")
+	data := bytesx.Copy(b.Bytes())
+	buffers.Put(b)
+	return data, nil
+}
+
+// 返回key, 为空时使用默认值
+func keyOr(key, def string) string {
+	if key == "" {
+		return def
+	}
+	return key
+}
+
+type jsonEncoder struct {
+	buf        *bytes.Buffer
+	timeFormat string
+	skip       bool // 当前key被设置为OmitKey, 跳过对应的value
+}
+
+func (enc *jsonEncoder) addKey(key string) {
+	enc.skip = key == OmitKey
+	if enc.skip {
+		return
+	}
+	if last := enc.buf.Bytes()[enc.buf.Len()-1]; last != '{' {
+		enc.buf.WriteByte(',')
+	}
+	appendJSONString(enc.buf, key)
+	enc.buf.WriteByte(':')
+}
+
+func (enc *jsonEncoder) addString(key, value string) {
+	enc.addKey(key)
+	if !enc.skip {
+		appendJSONString(enc.buf, value)
+	}
+}
+
+func (enc *jsonEncoder) appendTime(t time.Time) {
+	if enc.skip {
+		return
+	}
+	text, numeric := formatTime(t, enc.timeFormat)
+	if numeric {
+		enc.buf.WriteString(text)
+	} else {
+		appendJSONString(enc.buf, text)
+	}
+}
+
+// 按照layout格式化时间, layout为空时使用time.RFC3339Nano, numeric表示结果是否为时间戳
+func formatTime(t time.Time, layout string) (text string, numeric bool) {
+	switch layout {
+	case TimeFormatEpoch:
+		return strconv.FormatFloat(float64(t.UnixNano())/float64(time.Second), 'f', -1, 64), true
+	case TimeFormatEpochMillis:
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10), true
+	case TimeFormatEpochNanos:
+		return strconv.FormatInt(t.UnixNano(), 10), true
+	case "":
+		return t.Format(time.RFC3339Nano), false
+	default:
+		return t.Format(layout), false
+	}
+}
+
+func (enc *jsonEncoder) addField(f Field) {
+	enc.addKey(f.Key)
+	if enc.skip {
+		return
+	}
+	switch f.Type {
+	case IntType, UintType, BoolType:
+		enc.buf.WriteString(f.valueString())
+	case FloatType:
+		v := math.Float64frombits(uint64(f.integer))
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			appendJSONString(enc.buf, f.valueString())
+		} else {
+			enc.buf.WriteString(f.valueString())
+		}
+	case TimeType:
+		enc.appendTime(f.iface.(time.Time))
+	case AnyType:
+		data, err := json.Marshal(f.iface)
+		if err != nil {
+			appendJSONString(enc.buf, fmt.Sprint(f.iface))
+		} else {
+			enc.buf.Write(data)
+		}
+	default:
+		appendJSONString(enc.buf, f.valueString())
+	}
+}
+
+const hexDigits = "0123456789abcdef"
+
+// 写入带引号并转义后的JSON字符串, 非法的UTF-8字符替换为�
+func appendJSONString(b *bytes.Buffer, s string) {
+	b.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			b.WriteString(s[start:i])
+			switch c {
+			case '"', '\\':
+				b.WriteByte('\\')
+				b.WriteByte(c)
+			case '\n':
+				b.WriteString(`\n`)
+			case '\r':
+				b.WriteString(`\r`)
+			case '\t':
+				b.WriteString(`\t`)
+			default:
+				b.WriteString(`\u00`)
+				b.WriteByte(hexDigits[c>>4])
+				b.WriteByte(hexDigits[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b.WriteString(s[start:i])
+			b.WriteString(`�`)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	b.WriteString(s[start:])
+	b.WriteByte('"')
+}
@@ -0,0 +1,33 @@
+package plogs
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestAppendJSONString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", `"plain"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`C:\path`, `"C:\\path"`},
+		{"a\nb\tc\rd", `"a\nb\tc\rd"`},
+		{"bell\x07\x1f", `"bell\u0007\u001f"`},
+		{"bad\xffutf8", "\"bad\ufffdutf8\""},
+		{"中文", `"中文"`},
+	}
+	for _, tt := range tests {
+		var b bytes.Buffer
+		appendJSONString(&b, tt.in)
+		if got := b.String(); got != tt.want {
+			t.Errorf("appendJSONString(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+		var decoded string
+		if err := json.Unmarshal(b.Bytes(), &decoded); err != nil {
+			t.Errorf("appendJSONString(%q) produced invalid JSON %s: %v", tt.in, b.String(), err)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package plogs
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pyihe/go-pkg/buffers"
+	bytesx "github.com/pyihe/go-pkg/bytes"
+	"github.com/pyihe/go-pkg/times"
+)
+
+const (
+	_VerbBegin  layoutVerb = iota // begin
+	verbLiteral                   // 原样输出的文本
+	verbTime                      // %time{layout}
+	verbLevel                     // %level
+	verbApp                       // %app
+	verbLogger                    // %logger
+	verbCaller                    // %caller
+	verbFunc                      // %func
+	verbMessage                   // %msg
+	verbFields                    // %fields
+)
+
+// DefaultLayout LayoutFormatter的默认布局, 与TextFormatter的字段顺序一致
+const DefaultLayout = "%time %level %app %logger %caller %msg%fields"
+
+type layoutVerb int
+
+// 零值的LayoutFormatter使用DefaultLayout, 只需要解析一次
+var defaultLayoutParts []layoutPart
+
+func init() {
+	parts, err := parseLayout(DefaultLayout)
+	assert(err != nil, "parse default layout failed")
+	defaultLayoutParts = parts
+}
+
+var layoutVerbs = map[string]layoutVerb{
+	"time":   verbTime,
+	"level":  verbLevel,
+	"app":    verbApp,
+	"logger": verbLogger,
+	"caller": verbCaller,
+	"func":   verbFunc,
+	"msg":    verbMessage,
+	"fields": verbFields,
+}
+
+// layoutPart 布局解析后的片段
+type layoutPart struct {
+	verb  layoutVerb // 类型
+	text  string     // 文本内容或者时间格式
+	width int        // 最小宽度, 不足时补空格
+	left  bool       // 是否左对齐
+}
+
+// LayoutFormatter 按照布局模板输出日志, 模板中支持的占位符:
+//
+//	%time{2006-01-02 15:04:05.000}  时间, 花括号中为时间格式, 省略时使用times.SlashWithMillFormat
+//	%level                          级别, 默认为大写的级别名称, 可以通过LevelLabels自定义
+//	%app                            应用名
+//	%logger                         Logger名称
+//	%caller                         调用者file:line
+//	%func                           调用者函数名
+//	%msg                            日志内容
+//	%fields                         结构化字段, 输出为" key=value"
+//	%%                              字符%
+//
+// 占位符可以指定宽度与对齐方式: %-5level表示左对齐、宽度至少为5, %10app表示右对齐;
+// 未指定宽度的占位符输出为空时(如没有设置app), 紧跟其后的空白分隔符也不会输出;
+// Panic级别日志的调用栈总是输出在该行之后
+type LayoutFormatter struct {
+	Location    *time.Location   // 时区, 为nil时使用本地时区, 如time.UTC或者time.LoadLocation("Asia/Shanghai")
+	LevelLabels map[Level]string // 自定义级别的显示文本, 如{LevelError: "ERR"}
+	parts       []layoutPart
+}
+
+// NewLayoutFormatter 解析布局模板, 模板为空时使用DefaultLayout
+func NewLayoutFormatter(layout string) (*LayoutFormatter, error) {
+	if layout == "" {
+		layout = DefaultLayout
+	}
+	parts, err := parseLayout(layout)
+	if err != nil {
+		return nil, err
+	}
+	return &LayoutFormatter{parts: parts}, nil
+}
+
+func parseLayout(layout string) ([]layoutPart, error) {
+	var parts []layoutPart
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			parts = append(parts, layoutPart{verb: verbLiteral, text: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(layout); {
+		if layout[i] != '%' {
+			literal.WriteByte(layout[i])
+			i++
+			continue
+		}
+		i++
+		if i < len(layout) && layout[i] == '%' {
+			literal.WriteByte('%')
+			i++
+			continue
+		}
+
+		var part layoutPart
+		if i < len(layout) && layout[i] == '-' {
+			part.left = true
+			i++
+		}
+		for i < len(layout) && layout[i] >= '0' && layout[i] <= '9' {
+			part.width = part.width*10 + int(layout[i]-'0')
+			i++
+		}
+		start := i
+		for i < len(layout) && layout[i] >= 'a' && layout[i] <= 'z' {
+			i++
+		}
+		name := layout[start:i]
+		verb, ok := layoutVerbs[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown layout verb %%%s at %d", name, start)
+		}
+		part.verb = verb
+		if i < len(layout) && layout[i] == '{' {
+			end := strings.IndexByte(layout[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '{' for %%%s", name)
+			}
+			part.text = layout[i+1 : i+end]
+			i += end + 1
+		}
+		flush()
+		parts = append(parts, part)
+	}
+	flush()
+	return parts, nil
+}
+
+func (f *LayoutFormatter) Format(e *Entry) ([]byte, error) {
+	parts := f.parts
+	if parts == nil {
+		parts = defaultLayoutParts
+	}
+
+	b := buffers.Get()
+	var empty bool // 上一个占位符的输出为空, 紧跟其后的空白分隔符不再输出
+	for _, part := range parts {
+		switch part.verb {
+		case verbLiteral:
+			if !(empty && strings.TrimSpace(part.text) == "") {
+				b.WriteString(part.text)
+			}
+			empty = false
+		case verbFields:
+			for _, field := range e.Fields {
+				b.WriteString(" ")
+				b.WriteString(field.Key)
+				b.WriteString("=")
+				writeTextValue(b, field.valueString())
+			}
+			empty = false
+		default:
+			value := f.value(part, e)
+			writePadded(b, value, part.width, part.left)
+			empty = value == "" && part.width == 0
+		}
+	}
+	if b.Len() == 0 || b.Bytes()[b.Len()-1] != '\n' {
+		b.WriteString("\n")
+	}
+	if e.Stack != "" {
+		b.WriteString(e.Stack)
+		if !strings.HasSuffix(e.Stack, "\n") {
+			b.WriteString("\n")
+		}
+	}
+
+	data := bytesx.Copy(b.Bytes())
+	buffers.Put(b)
+	return data, nil
+}
+
+func (f *LayoutFormatter) value(part layoutPart, e *Entry) string {
+	switch part.verb {
+	case verbTime:
+		t := e.Time
+		if f.Location != nil {
+			t = t.In(f.Location)
+		}
+		layout := part.text
+		if layout == "" {
+			layout = times.SlashWithMillFormat
+		}
+		return t.Format(layout)
+	case verbLevel:
+		if label, ok := f.LevelLabels[e.Level]; ok {
+			return label
+		}
+		return strings.ToUpper(e.Level.String())
+	case verbApp:
+		return e.App
+	case verbLogger:
+		return e.Logger
+	case verbCaller:
+		return e.Caller.String()
+	case verbFunc:
+		return e.Caller.Function
+	case verbMessage:
+		return e.Message
+	default:
+		return ""
+	}
+}
+
+func writePadded(b *bytes.Buffer, value string, width int, left bool) {
+	padding := width - utf8.RuneCountInString(value)
+	if padding > 0 && !left {
+		b.WriteString(strings.Repeat(" ", padding))
+	}
+	b.WriteString(value)
+	if padding > 0 && left {
+		b.WriteString(strings.Repeat(" ", padding))
+	}
+}
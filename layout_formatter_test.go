@@ -0,0 +1,35 @@
+package plogs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLayoutFormatterSkipsEmptyColumns(t *testing.T) {
+	ts := time.Date(2026, time.October, 18, 15, 4, 5, 0, time.UTC)
+	caller := EntryCaller{Defined: true, File: "/a.go", Line: 3}
+	tests := []struct {
+		name   string
+		layout string
+		entry  Entry
+		want   string
+	}{
+		{"no app and logger", "", Entry{Time: ts, Level: LevelInfo, Caller: caller, Message: "hi"}, "2026/10/18 15:04:05.000000 INFO /a.go:3 hi\n"},
+		{"all columns", "", Entry{Time: ts, Level: LevelInfo, App: "app", Logger: "db", Caller: caller, Message: "hi"}, "2026/10/18 15:04:05.000000 INFO app db /a.go:3 hi\n"},
+		{"width keeps padding", "%-5level|%3app|%msg", Entry{Time: ts, Level: LevelWarn, Message: "hi"}, "WARN |   |hi\n"},
+		{"non-blank separator kept", "[%app] %msg", Entry{Time: ts, Level: LevelInfo, Message: "hi"}, "[] hi\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewLayoutFormatter(tt.layout)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.Location = time.UTC
+			got, _ := f.Format(&tt.entry)
+			if string(got) != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package plogs
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// Level 返回Logger当前生效的日志级别: 优先使用名称匹配的级别配置, 否则使用全局级别
+func (l *Logger) Level() Level {
+	if level, ok := l.config.namedLevel(l.name); ok {
+		return level
+	}
+	return l.config.loadLevel()
+}
+
+// SetLevel 运行时修改日志级别, 可以安全地在其他goroutine中调用;
+// 对于通过Named创建的Logger, 修改的是该名称(及其子Logger)的级别
+func (l *Logger) SetLevel(level Level) {
+	l.updateLevel(func(Level) Level {
+		return level
+	})
+}
+
+// EnableLevel 在当前级别的基础上开启levels中的级别
+func (l *Logger) EnableLevel(levels Level) {
+	l.updateLevel(func(old Level) Level {
+		return old | levels
+	})
+}
+
+// DisableLevel 在当前级别的基础上关闭levels中的级别
+func (l *Logger) DisableLevel(levels Level) {
+	l.updateLevel(func(old Level) Level {
+		return old &^ levels
+	})
+}
+
+// SetNamedLevel 运行时为名称为name的Logger及其子Logger设置日志级别, 规则同WithNamedLevel
+func (l *Logger) SetNamedLevel(name string, level Level) {
+	l.config.setNamedLevel(name, level)
+	l.levelChanged()
+}
+
+// RemoveNamedLevel 删除名称为name的级别配置, 该名称的Logger将重新使用上一级或者全局的级别
+func (l *Logger) RemoveNamedLevel(name string) {
+	l.config.removeNamedLevel(name)
+}
+
+// NamedLevels 返回所有按名称设置的日志级别
+func (l *Logger) NamedLevels() map[string]Level {
+	levels := l.config.loadNamedLevels()
+	result := make(map[string]Level, len(levels))
+	for name, level := range levels {
+		result[name] = level
+	}
+	return result
+}
+
+func (l *Logger) updateLevel(update func(old Level) Level) {
+	config := l.config
+	if l.name == "" {
+		for {
+			old := atomic.LoadInt64(&config.logLevel)
+			if atomic.CompareAndSwapInt64(&config.logLevel, old, int64(update(Level(old)))) {
+				break
+			}
+		}
+	} else {
+		config.levelMu.Lock()
+		config.storeNamedLevel(l.name, update(l.Level()))
+		config.levelMu.Unlock()
+	}
+	l.levelChanged()
+}
+
+// 开启了新的级别时, 需要补充创建对应级别的writer
+func (l *Logger) levelChanged() {
+	if err := l.addSeparatedWriter(); err != nil {
+		l.Errorw("create writer for enabled level failed", Err(err))
+	}
+}
+
+func (c *LogConfig) loadLevel() Level {
+	return Level(atomic.LoadInt64(&c.logLevel))
+}
+
+func (c *LogConfig) storeLevel(level Level) {
+	atomic.StoreInt64(&c.logLevel, int64(level))
+}
+
+func (c *LogConfig) loadNamedLevels() map[string]Level {
+	levels, _ := c.namedLevels.Load().(map[string]Level)
+	return levels
+}
+
+func (c *LogConfig) setNamedLevel(name string, level Level) {
+	c.levelMu.Lock()
+	c.storeNamedLevel(name, level)
+	c.levelMu.Unlock()
+}
+
+func (c *LogConfig) removeNamedLevel(name string) {
+	name = strings.TrimSuffix(name, ".*")
+	c.levelMu.Lock()
+	defer c.levelMu.Unlock()
+
+	old := c.loadNamedLevels()
+	if _, ok := old[name]; !ok {
+		return
+	}
+	levels := make(map[string]Level, len(old))
+	for n, level := range old {
+		if n != name {
+			levels[n] = level
+		}
+	}
+	c.namedLevels.Store(levels)
+}
+
+// 写时复制, 调用方需要持有levelMu
+func (c *LogConfig) storeNamedLevel(name string, level Level) {
+	name = strings.TrimSuffix(name, ".*")
+	if name == "" {
+		return
+	}
+	old := c.loadNamedLevels()
+	levels := make(map[string]Level, len(old)+1)
+	for n, l := range old {
+		levels[n] = l
+	}
+	levels[name] = level
+	c.namedLevels.Store(levels)
+}
+
+// 按照名称层级由深到浅查找匹配的日志级别
+func (c *LogConfig) namedLevel(name string) (Level, bool) {
+	if name == "" {
+		return 0, false
+	}
+	levels := c.loadNamedLevels()
+	if len(levels) == 0 {
+		return 0, false
+	}
+	for {
+		if level, ok := levels[name]; ok {
+			return level, true
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return 0, false
+		}
+		name = name[:i]
+	}
+}
+
+// 全局级别与所有按名称设置的级别的并集, 用于确定需要创建哪些级别的writer
+func (c *LogConfig) allLevels() Level {
+	level := c.loadLevel()
+	for _, l := range c.loadNamedLevels() {
+		level |= l
+	}
+	return level
+}
@@ -0,0 +1,288 @@
+package plogs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// 自定义级别可用的最大bit位, 保证在32位平台上Level也不会溢出
+const _LevelMax Level = 1 << 30
+
+var (
+	ErrInvalidLevelName = errors.New("level name is empty, reserved or contains '+', '|', ','")
+	ErrTooManyLevels    = errors.New("too many levels registered")
+)
+
+// levelDesc 级别的描述信息
+type levelDesc struct {
+	level    Level  // 级别
+	name     string // 名称
+	prefix   string // 文本格式输出时的前缀, 如"[E] "
+	dir      string // 区分级别记录时的子目录, 如"errors"
+	severity int    // 严重程度, 数值越大越严重
+}
+
+// levelTable 已注册级别的快照, 注册新级别时整体替换
+type levelTable struct {
+	descs  map[Level]*levelDesc // 级别 -> 描述
+	names  map[string]Level     // 名称 -> 级别
+	sorted []Level              // 按严重程度由高到低排序
+	next   Level                // 下一个可分配的bit位
+}
+
+var (
+	registryMu sync.Mutex   // 注册级别时加锁
+	registry   atomic.Value // *levelTable
+)
+
+func init() {
+	registry.Store(&levelTable{
+		descs: make(map[Level]*levelDesc),
+		names: make(map[string]Level),
+		next:  _LevelEnd << 1,
+	})
+	builtins := []levelDesc{
+		{level: LevelPanic, name: "panic", prefix: "[P] ", dir: "panics", severity: 60},
+		{level: LevelFatal, name: "fatal", prefix: "[F] ", dir: "fatals", severity: 50},
+		{level: LevelError, name: "error", prefix: "[E] ", dir: "errors", severity: 40},
+		{level: LevelWarn, name: "warn", prefix: "[W] ", dir: "warns", severity: 30},
+		{level: LevelInfo, name: "info", prefix: "[I] ", dir: "infos", severity: 20},
+		{level: LevelDebug, name: "debug", prefix: "[D] ", dir: "debugs", severity: 10},
+	}
+	for i := range builtins {
+		storeLevel(&builtins[i])
+	}
+}
+
+// RegisterLevel 注册自定义级别, 返回分配到的Level, 之后可以像内置级别一样用于WithLogLevel以及区分级别记录
+//
+// name: 级别名称(不区分大小写), 不能与已注册的级别重复, 不能是ParseLevel保留的all、none、off、*, 也不能包含"+"、"|"、","
+// prefix: 文本格式输出的前缀, 为空时使用"[名称首字母大写] "
+// dir: 区分级别记录时的子目录, 为空时使用"名称s", 不能与已注册的级别重复
+// severity: 严重程度, 内置级别依次为Debug(10), Info(20), Warn(30), Error(40), Fatal(50), Panic(60)
+//
+// 需要在创建Logger之前注册, 才能为该级别创建对应的文件writer
+func RegisterLevel(name, prefix, dir string, severity int) (Level, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !validLevelName(name) {
+		return 0, ErrInvalidLevelName
+	}
+	if prefix == "" {
+		r, _ := utf8.DecodeRuneInString(name)
+		prefix = "[" + strings.ToUpper(string(r)) + "] "
+	}
+	if dir == "" {
+		dir = name + "s"
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	table := loadLevels()
+	if _, exist := table.names[name]; exist {
+		return 0, fmt.Errorf("level %q already registered", name)
+	}
+	for _, desc := range table.descs {
+		if strings.EqualFold(desc.dir, dir) {
+			return 0, fmt.Errorf("level dir %q already used by level %q", dir, desc.name)
+		}
+	}
+	if table.next <= 0 || table.next > _LevelMax {
+		return 0, ErrTooManyLevels
+	}
+	desc := &levelDesc{
+		level:    table.next,
+		name:     name,
+		prefix:   prefix,
+		dir:      dir,
+		severity: severity,
+	}
+	storeLevel(desc)
+	return desc.level, nil
+}
+
+// 级别名称需要能够被ParseLevel原样解析, 保证String、MarshalText的结果可以通过UnmarshalText还原
+func validLevelName(name string) bool {
+	switch name {
+	case "", "all", "*", "none", "off":
+		return false
+	}
+	return !strings.ContainsAny(name, "+|,")
+}
+
+// MustRegisterLevel 同RegisterLevel, 注册失败时panic, 便于在包级变量中使用
+func MustRegisterLevel(name, prefix, dir string, severity int) Level {
+	level, err := RegisterLevel(name, prefix, dir, severity)
+	assert(err != nil, fmt.Sprintf("register level %s: %v", name, err))
+	return level
+}
+
+func loadLevels() *levelTable {
+	return registry.Load().(*levelTable)
+}
+
+// 写时复制, 调用方需要保证串行调用
+func storeLevel(desc *levelDesc) {
+	old := loadLevels()
+	table := &levelTable{
+		descs:  make(map[Level]*levelDesc, len(old.descs)+1),
+		names:  make(map[string]Level, len(old.names)+1),
+		sorted: make([]Level, 0, len(old.sorted)+1),
+		next:   old.next,
+	}
+	for level, d := range old.descs {
+		table.descs[level] = d
+	}
+	for name, level := range old.names {
+		table.names[name] = level
+	}
+	table.descs[desc.level] = desc
+	table.names[desc.name] = desc.level
+	table.sorted = append(table.sorted, old.sorted...)
+	table.sorted = append(table.sorted, desc.level)
+	sort.SliceStable(table.sorted, func(i, j int) bool {
+		return table.descs[table.sorted[i]].severity > table.descs[table.sorted[j]].severity
+	})
+	if desc.level >= table.next {
+		table.next = desc.level << 1
+	}
+	registry.Store(table)
+}
+
+func lookupLevel(level Level) (*levelDesc, bool) {
+	desc, ok := loadLevels().descs[level]
+	return desc, ok
+}
+
+// 按严重程度由高到低返回所有已注册的级别
+func registeredLevels() []Level {
+	return loadLevels().sorted
+}
+
+// 所有已注册级别的组合
+func allLevelMask() (mask Level) {
+	for _, level := range registeredLevels() {
+		mask |= level
+	}
+	return
+}
+
+// Severity 返回级别的严重程度, 未注册的级别或者多个级别的组合返回0
+func (l Level) Severity() int {
+	if desc, ok := lookupLevel(l); ok {
+		return desc.severity
+	}
+	return 0
+}
+
+// AtLeast 返回严重程度不低于level的所有已注册级别的组合, 如AtLeast(LevelWarn)即"Warn及以上"
+func AtLeast(level Level) (mask Level) {
+	desc, ok := lookupLevel(level)
+	if !ok {
+		return 0
+	}
+	for _, l := range registeredLevels() {
+		if d, _ := lookupLevel(l); d.severity >= desc.severity {
+			mask |= l
+		}
+	}
+	return
+}
+
+// ParseLevel 解析级别字符串(不区分大小写), 支持以下格式:
+//
+//	"warn"          单个级别
+//	"warn+"         Warn及以上级别
+//	"error|warn"    多个级别的组合, 也可以使用","分隔
+//	"all"/"none"    所有级别/不输出
+func ParseLevel(text string) (Level, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	switch text {
+	case "":
+		return 0, ErrInvalidLevelName
+	case "all", "*":
+		return allLevelMask(), nil
+	case "none", "off":
+		return 0, nil
+	}
+
+	var mask Level
+	table := loadLevels()
+	for _, token := range strings.FieldsFunc(text, func(r rune) bool { return r == '|' || r == ',' }) {
+		token = strings.TrimSpace(token)
+		above := strings.HasSuffix(token, "+")
+		token = strings.TrimSuffix(token, "+")
+		level, ok := table.names[token]
+		if !ok {
+			return 0, fmt.Errorf("unknown level %q", token)
+		}
+		if above {
+			level = AtLeast(level)
+		}
+		mask |= level
+	}
+	return mask, nil
+}
+
+// String 返回级别的名称, 多个级别的组合使用"|"连接, 恰好为"某级别及以上"时返回如"warn+"
+func (l Level) String() string {
+	if l == 0 {
+		return "none"
+	}
+	if desc, ok := lookupLevel(l); ok {
+		return desc.name
+	}
+
+	var names []string
+	var lowest *levelDesc
+	for _, level := range registeredLevels() {
+		if l&level == level {
+			lowest, _ = lookupLevel(level)
+			names = append(names, lowest.name)
+		}
+	}
+	if rest := l &^ allLevelMask(); rest != 0 {
+		names = append(names, fmt.Sprintf("level(%d)", int(rest)))
+	} else if lowest != nil && AtLeast(lowest.level) == l {
+		return lowest.name + "+"
+	}
+	return strings.Join(names, "|")
+}
+
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// UnmarshalJSON 除了级别字符串, 也兼容以数字表示的级别组合
+func (l *Level) UnmarshalJSON(data []byte) error {
+	if n, err := strconv.Atoi(string(data)); err == nil {
+		*l = Level(n)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return l.UnmarshalText([]byte(text))
+}
+
+// Set 实现flag.Value, 可以直接通过flag.Var从命令行参数读取级别
+func (l *Level) Set(text string) error {
+	return l.UnmarshalText([]byte(text))
+}
@@ -0,0 +1,102 @@
+package plogs
+
+import "testing"
+
+func TestRegisterLevelDefaultPrefix(t *testing.T) {
+	// 级别注册后无法注销, 使用-count多次运行时直接使用已注册的级别
+	level, err := ParseLevel("审计")
+	if err != nil {
+		if level, err = RegisterLevel("审计", "", "", 45); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := level.prefix(); got != "[审] " {
+		t.Errorf("prefix() = %q, want %q", got, "[审] ")
+	}
+}
+
+// 注册测试使用的自定义级别, 级别无法注销, 多次运行时直接使用已注册的级别
+func testLevel(t *testing.T, name string, severity int) Level {
+	t.Helper()
+	if level, err := ParseLevel(name); err == nil {
+		return level
+	}
+	level, err := RegisterLevel(name, "", "", severity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return level
+}
+
+func TestLevelStringRoundTrip(t *testing.T) {
+	notice := testLevel(t, "notice", 25)
+	tests := []struct {
+		level Level
+		text  string
+	}{
+		{LevelWarn, "warn"},
+		{AtLeast(LevelWarn), "warn+"},
+		{LevelError | LevelWarn, "error|warn"},
+		{notice, "notice"},
+		{AtLeast(notice), "notice+"},
+		{notice | LevelDebug, "notice|debug"},
+		{0, "none"},
+	}
+	for _, tt := range tests {
+		if got := tt.level.String(); got != tt.text {
+			t.Errorf("Level(%d).String() = %q, want %q", tt.level, got, tt.text)
+		}
+		parsed, err := ParseLevel(tt.level.String())
+		if err != nil || parsed != tt.level {
+			t.Errorf("ParseLevel(%q) = %d, %v; want %d", tt.level.String(), parsed, err, tt.level)
+		}
+
+		text, _ := tt.level.MarshalText()
+		var unmarshaled Level
+		if err := unmarshaled.UnmarshalText(text); err != nil || unmarshaled != tt.level {
+			t.Errorf("UnmarshalText(%q) = %d, %v; want %d", text, unmarshaled, err, tt.level)
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    Level
+		wantErr bool
+	}{
+		{"WARN+", AtLeast(LevelWarn), false},
+		{" error , warn ", LevelError | LevelWarn, false},
+		{"error|warn+", AtLeast(LevelWarn), false},
+		{"all", allLevelMask(), false},
+		{"*", allLevelMask(), false},
+		{"off", 0, false},
+		{"", 0, true},
+		{"bogus", 0, true},
+		{"warn|bogus", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.text)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("ParseLevel(%q) = %d, %v; want %d, err %v", tt.text, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLevelUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data string
+		want Level
+	}{
+		{`"warn+"`, AtLeast(LevelWarn)},
+		{`"error|warn"`, LevelError | LevelWarn},
+		{`4`, LevelError},
+		{`"none"`, 0},
+	}
+	for _, tt := range tests {
+		var got Level
+		if err := got.UnmarshalJSON([]byte(tt.data)); err != nil || got != tt.want {
+			t.Errorf("UnmarshalJSON(%s) = %d, %v; want %d", tt.data, got, err, tt.want)
+		}
+	}
+}
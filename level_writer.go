@@ -1,81 +1,117 @@
 package plogs
 
 import (
-	"github.com/pyihe/plogs/internal"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
 	"github.com/pyihe/plogs/pkg"
+	"github.com/pyihe/plogs/writer"
 )
 
-type levelWriter struct {
-	internal.LogWriter
-	level Level
-}
-
 func (l *Logger) addLevelWriter() error {
-	var err error
 	var config = l.config
-	var allLevels = []Level{
-		LevelPanic, LevelFatal, LevelError, LevelWarn, LevelInfo, LevelDebug,
+
+	routes := config.routes
+	if routes == nil && config.logPath != "" {
+		routes = config.fileOption.Routes()
+	}
+	for _, r := range routes {
+		l.routes = append(l.routes, newRoute(r))
 	}
 
-	if config.stdout {
-		writer := &levelWriter{
-			level: _LevelBegin,
+	l.addConsoleWriter()
+
+	for _, r := range l.routes {
+		if (len(r.dirs) > 0 || r.levelDir) && config.logPath == "" {
+			return errors.New("log path is required when routing to files")
 		}
-		writer.LogWriter, _ = internal.NewStdWriter(l.ctx, &l.waiter)
-		l.writer.AddWriter(writer)
+		for _, dir := range r.dirs {
+			if l.writer.Exist(fileWriterName(dir)) {
+				continue
+			}
+			if err := l.addFileWriter(dir, dirLabel(dir), false); err != nil {
+				return err
+			}
+		}
+	}
+	return l.addSeparatedWriter()
+}
+
+// 区分级别记录时, 为所有可能输出的级别创建writer; 运行时修改日志级别后也需要调用以补充缺少的writer
+func (l *Logger) addSeparatedWriter() error {
+	var levels Level
+	for _, r := range l.routes {
+		if r.levelDir {
+			levels |= r.levels
+		}
+	}
+	if levels == 0 {
+		return nil
 	}
 
-	if config.logPath == "" {
+	l.writerMu.Lock()
+	defer l.writerMu.Unlock()
+	if atomic.LoadInt32(&l.closed) == 1 {
 		return nil
 	}
 
-	switch config.fileOption {
-	case WriteByLevelMerged:
-		writer := &levelWriter{
-			level: _LevelEnd,
+	var enabled = l.config.allLevels() & levels
+	for _, level := range registeredLevels() {
+		if (enabled&level) != level || l.writer.Exist(fileWriterName(subPath(level))) {
+			continue
 		}
-		writer.LogWriter, err = internal.NewFileWriter(l.ctx, &l.waiter, config.logPath, "temp.log", config.maxSize, config.maxAge)
-		if err != nil {
+		if err := l.addFileWriter(subPath(level), level.String(), l.started); err != nil {
 			return err
 		}
-		l.writer.AddWriter(writer)
-	case WriteByLevelSeparated:
-		for _, level := range allLevels {
-			if (l.config.logLevel & level) == level {
-				writer := &levelWriter{
-					level: level,
-				}
-				writer.LogWriter, err = internal.NewFileWriter(l.ctx, &l.waiter, pkg.JoinPath(config.logPath, subPath(level)), "temp.log", config.maxSize, config.maxAge)
-				if err != nil {
-					return err
-				}
-				l.writer.AddWriter(writer)
-			}
-		}
-	case WriteByBoth:
-		targetLevel := make([]Level, 0, 8)
-		targetLevel = append(targetLevel, _LevelEnd)
-		for _, level := range allLevels {
-			if (l.config.logLevel & level) == level {
-				targetLevel = append(targetLevel, level)
-			}
-		}
-		for _, level := range targetLevel {
-			writer := &levelWriter{
-				level: level,
-			}
-			writer.LogWriter, err = internal.NewFileWriter(l.ctx, &l.waiter, pkg.JoinPath(config.logPath, subPath(level)), "temp.log", config.maxSize, config.maxAge)
-			if err != nil {
-				return err
-			}
-			l.writer.AddWriter(writer)
-		}
 	}
 	return nil
 }
 
-func (lw *levelWriter) Name() string {
-	return subPath(lw.level)
+// 在日志目录的dir子目录下创建文件writer, dir为空时直接写入日志目录; label用于替换文件名中的{level}
+func (l *Logger) addFileWriter(dir, label string, start bool) error {
+	fw, err := writer.NewFileWriter(pkg.JoinPath(l.config.logPath, dir), l.expandFileName(l.config.fileName, label),
+		writer.WithName(fileWriterName(dir)),
+		writer.WithMaxSize(l.config.maxSize),
+		writer.WithMaxAge(l.config.maxAge),
+		writer.WithMaxBackups(l.config.maxBackups),
+		writer.WithMaxTotalSize(l.config.maxTotalSize),
+		writer.WithRotateInterval(l.config.rotateInterval, l.config.rotateLocation),
+		writer.WithRotateName(l.expandFileName(l.config.rotateName, label)),
+		writer.WithCompressor(l.config.compressor),
+	)
+	if err != nil {
+		return err
+	}
+	if start {
+		fw.Start()
+	}
+	return l.writer.AddWriter(FormatWriter(fw, l.config.fileFormatter))
+}
+
+// 替换文件名中的{app}与{level}; 切割后文件名中的{pid}等由writer处理, 保证保留策略也能匹配之前的进程切割的文件
+func (l *Logger) expandFileName(pattern, label string) string {
+	return strings.NewReplacer(
+		"{app}", l.config.name,
+		"{level}", label,
+	).Replace(pattern)
+}
+
+// 非级别目录中的文件名里{level}的值: 日志目录下为"all", 子目录下为子目录名, 如"critical"
+func dirLabel(dir string) string {
+	if dir == "" {
+		return "all"
+	}
+	return filepath.Base(dir)
+}
+
+// 日志文件writer的名称: 日志目录下的文件为"plogs.file", 子目录下的文件为"plogs.file.errors"等
+func fileWriterName(dir string) string {
+	if dir != "" {
+		return strings.ToLower(builtinPrefix + "file." + dir)
+	}
+	return builtinPrefix + "file"
 }
 
 func assert(b bool, text string) {
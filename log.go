@@ -1,49 +1,115 @@
 package plogs
 
+import "context"
+
+func Log(level Level, args ...interface{}) {
+	std().Log(level, args...)
+}
+
+func Logf(level Level, template string, args ...interface{}) {
+	std().Logf(level, template, args...)
+}
+
+func Logw(level Level, message string, keysAndValues ...interface{}) {
+	std().Logw(level, message, keysAndValues...)
+}
+
+func LogCtx(ctx context.Context, level Level, message string, keysAndValues ...interface{}) {
+	std().LogCtx(ctx, level, message, keysAndValues...)
+}
+
 func Panic(args ...interface{}) {
-	defaultLogger.Panic(args...)
+	std().Panic(args...)
 }
 
 func Panicf(template string, args ...interface{}) {
-	defaultLogger.Panicf(template, args...)
+	std().Panicf(template, args...)
+}
+
+func Panicw(message string, keysAndValues ...interface{}) {
+	std().Panicw(message, keysAndValues...)
+}
+
+func PanicCtx(ctx context.Context, message string, keysAndValues ...interface{}) {
+	std().PanicCtx(ctx, message, keysAndValues...)
 }
 
 func Fatal(args ...interface{}) {
-	defaultLogger.Fatal(args...)
+	std().Fatal(args...)
 }
 
 func Fatalf(template string, args ...interface{}) {
-	defaultLogger.Fatalf(template, args...)
+	std().Fatalf(template, args...)
+}
+
+func Fatalw(message string, keysAndValues ...interface{}) {
+	std().Fatalw(message, keysAndValues...)
+}
+
+func FatalCtx(ctx context.Context, message string, keysAndValues ...interface{}) {
+	std().FatalCtx(ctx, message, keysAndValues...)
 }
 
 func Error(args ...interface{}) {
-	defaultLogger.Error(args...)
+	std().Error(args...)
 }
 
 func Errorf(template string, args ...interface{}) {
-	defaultLogger.Errorf(template, args...)
+	std().Errorf(template, args...)
+}
+
+func Errorw(message string, keysAndValues ...interface{}) {
+	std().Errorw(message, keysAndValues...)
+}
+
+func ErrorCtx(ctx context.Context, message string, keysAndValues ...interface{}) {
+	std().ErrorCtx(ctx, message, keysAndValues...)
 }
 
 func Warn(args ...interface{}) {
-	defaultLogger.Warn(args...)
+	std().Warn(args...)
 }
 
 func Warnf(template string, args ...interface{}) {
-	defaultLogger.Warnf(template, args...)
+	std().Warnf(template, args...)
+}
+
+func Warnw(message string, keysAndValues ...interface{}) {
+	std().Warnw(message, keysAndValues...)
+}
+
+func WarnCtx(ctx context.Context, message string, keysAndValues ...interface{}) {
+	std().WarnCtx(ctx, message, keysAndValues...)
 }
 
 func Info(args ...interface{}) {
-	defaultLogger.Info(args...)
+	std().Info(args...)
 }
 
 func Infof(template string, args ...interface{}) {
-	defaultLogger.Infof(template, args...)
+	std().Infof(template, args...)
+}
+
+func Infow(message string, keysAndValues ...interface{}) {
+	std().Infow(message, keysAndValues...)
+}
+
+func InfoCtx(ctx context.Context, message string, keysAndValues ...interface{}) {
+	std().InfoCtx(ctx, message, keysAndValues...)
 }
 
 func Debug(args ...interface{}) {
-	defaultLogger.Debug(args...)
+	std().Debug(args...)
 }
 
 func Debugf(template string, args ...interface{}) {
-	defaultLogger.Debugf(template, args...)
+	std().Debugf(template, args...)
+}
+
+func Debugw(message string, keysAndValues ...interface{}) {
+	std().Debugw(message, keysAndValues...)
+}
+
+func DebugCtx(ctx context.Context, message string, keysAndValues ...interface{}) {
+	std().DebugCtx(ctx, message, keysAndValues...)
 }
@@ -0,0 +1,103 @@
+package plogs
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pyihe/go-pkg/buffers"
+	bytesx "github.com/pyihe/go-pkg/bytes"
+)
+
+// LogfmtFormatter 每条日志编码为一行logfmt格式的key=value, 包含空白、引号、等号等字符的值会加上引号并转义,
+// key为空时使用默认值, 设置为OmitKey时不输出
+type LogfmtFormatter struct {
+	TimeKey     string // 时间, 默认"ts"
+	LevelKey    string // 级别, 默认"level"
+	AppKey      string // 应用名, 默认"app"
+	LoggerKey   string // Logger名称, 默认"logger"
+	CallerKey   string // 调用者, 默认"caller"
+	FunctionKey string // 调用者函数名, 默认"func"
+	MessageKey  string // 日志内容, 默认"msg"
+	StackKey    string // 调用栈, 默认"stack"
+	TimeFormat  string // 时间格式, 默认time.RFC3339Nano, 也可以使用TimeFormatEpoch等输出时间戳
+}
+
+func (f *LogfmtFormatter) Format(e *Entry) ([]byte, error) {
+	b := buffers.Get()
+	timeText, _ := formatTime(e.Time, f.TimeFormat)
+
+	writeLogfmtPair(b, keyOr(f.TimeKey, "ts"), timeText)
+	writeLogfmtPair(b, keyOr(f.LevelKey, "level"), e.Level.String())
+	if e.App != "" {
+		writeLogfmtPair(b, keyOr(f.AppKey, "app"), e.App)
+	}
+	if e.Logger != "" {
+		writeLogfmtPair(b, keyOr(f.LoggerKey, "logger"), e.Logger)
+	}
+	if e.Caller.Defined {
+		writeLogfmtPair(b, keyOr(f.CallerKey, "caller"), e.Caller.String())
+	}
+	if e.Caller.Function != "" {
+		writeLogfmtPair(b, keyOr(f.FunctionKey, "func"), e.Caller.Function)
+	}
+	writeLogfmtPair(b, keyOr(f.MessageKey, "msg"), e.Message)
+	for _, field := range e.Fields {
+		value := field.valueString()
+		if field.Type == TimeType {
+			value, _ = formatTime(field.iface.(time.Time), f.TimeFormat)
+		}
+		writeLogfmtPair(b, field.Key, value)
+	}
+	if e.Stack != "" {
+		writeLogfmtPair(b, keyOr(f.StackKey, "stack"), e.Stack)
+	}
+
+	b.WriteString("\n")
+	data := bytesx.Copy(b.Bytes())
+	buffers.Put(b)
+	return data, nil
+}
+
+func writeLogfmtPair(b *bytes.Buffer, key, value string) {
+	if key == OmitKey {
+		return
+	}
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	writeLogfmtKey(b, key)
+	b.WriteByte('=')
+	if logfmtNeedsQuote(value) {
+		b.WriteString(strconv.Quote(value))
+	} else {
+		b.WriteString(value)
+	}
+}
+
+// key中不允许出现空白、引号、等号以及不可打印字符, 替换为'_'
+func writeLogfmtKey(b *bytes.Buffer, key string) {
+	if key == "" {
+		key = badKey
+	}
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+}
+
+func logfmtNeedsQuote(value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package plogs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteLogfmtPair(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		want  string
+	}{
+		{"msg", "plain", `msg=plain`},
+		{"msg", "", `msg=""`},
+		{"msg", "two words", `msg="two words"`},
+		{"msg", "a=b", `msg="a=b"`},
+		{"msg", `say "hi"`, `msg="say \"hi\""`},
+		{"msg", `C:\path`, `msg="C:\\path"`},
+		{"msg", "line1\nline2", `msg="line1\nline2"`},
+		{"msg", "中文", `msg=中文`},
+		{"bad key=", "v", `bad_key_=v`},
+		{"", "v", badKey + `=v`},
+	}
+	for _, tt := range tests {
+		var b bytes.Buffer
+		writeLogfmtPair(&b, tt.key, tt.value)
+		if got := b.String(); got != tt.want {
+			t.Errorf("writeLogfmtPair(%q, %q) = %s, want %s", tt.key, tt.value, got, tt.want)
+		}
+	}
+}
@@ -3,167 +3,249 @@ package plogs
 import (
 	"context"
 	"os"
-	"runtime"
 	"runtime/debug"
-	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/pyihe/go-pkg/buffers"
-	"github.com/pyihe/go-pkg/bytes"
-	"github.com/pyihe/go-pkg/syncs"
-	"github.com/pyihe/go-pkg/times"
-	"github.com/pyihe/plogs/internal"
 	"github.com/pyihe/plogs/pkg"
+	"github.com/pyihe/plogs/writer"
 )
 
-var defaultLogger Logger
+var defaultLogger atomic.Value // 包级函数使用的默认Logger: *defaults
+
+// defaults 包级函数比直接调用Logger的方法多一层调用栈, 因此额外保存一个跳过该层的Logger
+type defaults struct {
+	logger *Logger // SetDefault设置的Logger
+	std    *Logger // 包级函数实际使用的Logger
+}
 
 type Logger struct {
-	closed int32                    // 是否关闭
-	ctx    context.Context          //
-	cancel context.CancelFunc       //
-	waiter syncs.WgWrapper          // waiter
-	once   sync.Once                // once
-	writer *internal.MultipeWriters // writer
-	config *LogConfig               // 配置
+	*logCore           // 同一个Logger派生出的子Logger共享
+	name       string  // Logger名称, 通过Named派生, 以"."分隔层级
+	fields     []Field // 绑定到该Logger的字段, 会添加到每一条日志中
+	callerSkip int     // 获取调用者时额外跳过的调用栈层数
 }
 
+// logCore Logger与其子Logger共享的writer与配置
+type logCore struct {
+	closed      int32                  // 是否关闭
+	started     bool                   // writer是否已经启动
+	writerMu    sync.Mutex             // 运行时添加writer与关闭时加锁
+	writer      *writer.MultipeWriters // writer
+	config      *LogConfig             // 配置
+	stdoutColor bool                   // 输出到stdout时是否着色
+	stderrColor bool                   // 输出到stderr时是否着色
+	callers     sync.Map               // 调用者信息缓存: pc -> *callerInfo
+	subs        atomic.Value           // 自定义writer的订阅: []*subscription, 写时复制
+	routes      []*route               // 路由规则, 初始化后不再修改
+}
+
+// NewLogger 每次调用都会创建一个新的Logger, 不同Logger之间的配置与输出互不影响
+// 进程内第一个创建的Logger会自动成为默认Logger, 可以通过SetDefault替换
 func NewLogger(opts ...Option) *Logger {
-	defaultLogger.once.Do(func() {
-		defaultLogger.closed = 0
-		defaultLogger.ctx, defaultLogger.cancel = context.WithCancel(context.Background())
-		defaultLogger.waiter = syncs.WgWrapper{}
-		defaultLogger.writer = internal.NewMultipeWriters()
-		defaultLogger.config = &LogConfig{
-			stdout:     false,
-			fileOption: WriteByLevelMerged,
-			logLevel:   LevelPanic | LevelFatal | LevelError | LevelWarn | LevelInfo | LevelDebug,
-			maxAge:     0,
-			maxSize:    0,
-			name:       "",
-			logPath:    "",
-		}
+	l := &Logger{logCore: &logCore{}}
+	l.closed = 0
+	l.writer = writer.NewMultipeWriters()
+	l.config = &LogConfig{
+		console:      ConsoleNone,
+		stderrLevels: AtLeast(LevelError),
+		fileOption:   WriteByLevelMerged,
+		logLevel:     int64(allLevelMask()),
+		callerMode:   CallerFull,
+		formatter:    &TextFormatter{},
+		colorMode:    ColorAuto,
+		palette:      DefaultPalette,
+		maxAge:       0,
+		maxSize:      0,
+		fileName:     "temp.log",
+		name:         "",
+		logPath:      "",
+	}
 
-		for _, op := range opts {
-			op(&defaultLogger)
-		}
+	for _, op := range opts {
+		op(l)
+	}
+
+	l.init()
+	l.start()
 
-		defaultLogger.init()
-		defaultLogger.start()
-	})
-	return &defaultLogger
+	defaultLogger.CompareAndSwap(nil, newDefaults(l))
+	return l
 }
 
-func (l *Logger) init() {
-	if err := l.addLevelWriter(); err != nil {
-		assert(true, err.Error())
+// SetDefault 设置包级函数(plogs.Info等)使用的默认Logger
+func SetDefault(l *Logger) {
+	defaultLogger.Store(newDefaults(l))
+}
+
+// Default 返回当前的默认Logger, 如果还没有创建过任何Logger则返回nil
+func Default() *Logger {
+	if d, ok := defaultLogger.Load().(*defaults); ok {
+		return d.logger
 	}
+	return nil
 }
 
-func (l *Logger) Close() {
-	if atomic.LoadInt32(&l.closed) == 1 {
-		return
+func newDefaults(l *Logger) *defaults {
+	return &defaults{
+		logger: l,
+		std:    l.WithCallerSkip(1),
 	}
-	atomic.StoreInt32(&l.closed, 1)
-	l.cancel()
-	l.writer.Stop()
-	l.waiter.Wait()
 }
 
-func (l *Logger) recover(message string) {
-	if (l.config.logLevel & LevelPanic) != LevelPanic {
-		return
+// 包级函数使用的Logger
+func std() *Logger {
+	if d, ok := defaultLogger.Load().(*defaults); ok {
+		return d.std
 	}
-	defer func() {
-		if err := recover(); err != nil {
-			msg := debug.Stack()
-			l.write(LevelPanic, msg)
-		}
-	}()
+	return nil
+}
 
-	panic(message)
+func (l *Logger) clone() *Logger {
+	child := *l
+	return &child
 }
 
-func (l *Logger) write(level Level, message []byte) {
-	config := l.config
-	multipeWriter := l.writer
-	outputLevel := make([]string, 0, 4)
+// With 返回绑定了字段的子Logger, 子Logger与父Logger共享writer与配置, 关闭任意一个都会关闭全部
+// 参数可以直接是Field, 也可以是交替出现的key、value
+func (l *Logger) With(args ...interface{}) *Logger {
+	if l == nil || len(args) == 0 {
+		return l
+	}
+	fields := sweetenFields(args)
+	child := l.clone()
+	child.fields = make([]Field, 0, len(l.fields)+len(fields))
+	child.fields = append(child.fields, l.fields...)
+	child.fields = append(child.fields, fields...)
+	return child
+}
 
-	if config.stdout {
-		outputLevel = append(outputLevel, subPath(_LevelBegin))
+// Named 返回名称为"父Logger名称.name"的子Logger, 名称会输出到日志中,
+// 并可以通过WithNamedLevel为其单独设置日志级别
+func (l *Logger) Named(name string) *Logger {
+	if l == nil || name == "" {
+		return l
 	}
-	switch config.fileOption {
-	case WriteByLevelMerged:
-		outputLevel = append(outputLevel, subPath(_LevelEnd))
-	case WriteByLevelSeparated:
-		outputLevel = append(outputLevel, subPath(level))
-	case WriteByBoth:
-		outputLevel = append(outputLevel, subPath(_LevelEnd))
-		outputLevel = append(outputLevel, subPath(level))
+	child := l.clone()
+	child.name = name
+	if l.name != "" {
+		child.name = l.name + "." + name
 	}
+	return child
+}
 
-	multipeWriter.WriteTo(message, outputLevel...)
+// Name 返回Logger的名称
+func (l *Logger) Name() string {
+	return l.name
 }
 
-func (l *Logger) log(level Level, message string) {
-	var (
-		now         = time.Now()
-		appName     = l.config.name                         // 应用名
-		levelPrefix = level.prefix()                        // 日志级别
-		timeDesc    = now.Format(times.SlashWithMillFormat) // 时间
-	)
+func (l *Logger) init() {
+	if err := l.addLevelWriter(); err != nil {
+		assert(true, err.Error())
+	}
+	for _, sub := range l.config.writers {
+		if err := l.subscribe(sub, false); err != nil {
+			assert(true, sub.writer.Name()+": "+err.Error())
+		}
+	}
+}
 
-	_, fileName, line, ok := runtime.Caller(3)
-	if !ok {
-		fileName = "???"
-		line = 0
+func (l *Logger) Close() {
+	if !atomic.CompareAndSwapInt32(&l.closed, 0, 1) {
+		return
 	}
+	l.writerMu.Lock()
+	defer l.writerMu.Unlock()
+	l.writer.Stop()
+}
 
-	b := buffers.Get()
-	// write app name
-	if appName != "" {
-		b.WriteString("[")
-		b.WriteString(appName)
-		b.WriteString("] ")
+func (l *Logger) write(e *Entry) {
+	var cache encodeCache
+	var written []string // 已经写入的writer, 多条规则指向同一个writer时只写入一次
+	if name, color := l.consoleOutput(e.Level); name != "" {
+		l.writeTo(name, e, &cache, color)
+		written = append(written, name)
 	}
-	// write prefix
-	b.WriteString(levelPrefix)
+	for _, name := range l.outputs(e) {
+		if contains(written, name) {
+			continue
+		}
+		l.writeTo(name, e, &cache, l.colorOf(name))
+		written = append(written, name)
+	}
+	for _, sub := range l.subscriptions() {
+		if sub.match(e) && !contains(written, sub.name) {
+			l.writeEntry(sub.writer, e, &cache, false)
+		}
+	}
+}
 
-	// write timedesc
-	b.WriteString("[")
-	b.WriteString(timeDesc)
-	b.WriteString("] ")
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
 
-	// write file
-	b.WriteString(fileName)
-	b.WriteString(":")
-	b.WriteString(strconv.FormatInt(int64(line), 10))
-	b.WriteString(" ")
+// 使用writer对应的Formatter编码后写入, 终端输出单独着色, 文件中始终不包含颜色
+func (l *Logger) writeTo(name string, e *Entry, cache *encodeCache, color bool) {
+	w, exist := l.writer.Get(name)
+	if !exist {
+		return
+	}
+	l.writeEntry(w, e, cache, color)
+}
 
-	// write message
-	b.WriteString(message)
+func (l *Logger) writeEntry(w writer.LogWriter, e *Entry, cache *encodeCache, color bool) {
+	data, ok := cache.encode(l.formatterOf(w), e)
+	if !ok {
+		return
+	}
+	if color {
+		data = l.config.palette.colorize(e.Level, data)
+	}
+	w.Write(data)
+}
 
-	//new line
-	b.WriteString("\n")
+func (l *Logger) log(level Level, message string, fields []Field) {
+	e := &Entry{
+		Time:    time.Now(),
+		Level:   level,
+		App:     l.config.name,
+		Logger:  l.name,
+		Message: message,
+		Fields:  fields,
+	}
+	if len(l.fields) > 0 {
+		e.Fields = make([]Field, 0, len(l.fields)+len(fields))
+		e.Fields = append(e.Fields, l.fields...)
+		e.Fields = append(e.Fields, fields...)
+	}
+	// Panic级别的日志需要记录调用栈
+	if level == LevelPanic {
+		e.Stack = string(debug.Stack())
+	}
 
-	logStr := bytes.Copy(b.Bytes())
-	buffers.Put(b)
+	l.captureCaller(e)
 
 	// 写入目标流
-	l.write(level, logStr)
-	return
+	l.write(e)
 }
 
 func (l *Logger) canOutput(level Level) bool {
+	if l == nil {
+		return false
+	}
 	if atomic.LoadInt32(&l.closed) == 1 {
 		return false
 	}
 	if !level.valid() {
 		return false
 	}
-	if (l.config.logLevel & level) != level {
+	if (l.Level() & level) != level {
 		return false
 	}
 	return true
@@ -171,31 +253,90 @@ func (l *Logger) canOutput(level Level) bool {
 
 func (l *Logger) start() {
 	assert(l.writer.Count() == 0, "where the log will be written?")
+	l.writerMu.Lock()
 	l.writer.Start()
+	l.started = true
+	l.writerMu.Unlock()
 }
 
 func (l *Logger) exit() {
-	if (l.config.logLevel & LevelFatal) != LevelFatal {
+	if (l.Level() & LevelFatal) != LevelFatal {
 		return
 	}
 	l.Close()
 	os.Exit(1)
 }
 
+// Log 以指定级别输出日志, 可用于通过RegisterLevel注册的自定义级别
+func (l *Logger) Log(level Level, args ...interface{}) {
+	if !l.canOutput(level) {
+		return
+	}
+	m := pkg.GetMessage("", args)
+	l.log(level, m, nil)
+	l.finish(level)
+}
+
+func (l *Logger) Logf(level Level, template string, args ...interface{}) {
+	if !l.canOutput(level) {
+		return
+	}
+	m := pkg.GetMessage(template, args)
+	l.log(level, m, nil)
+	l.finish(level)
+}
+
+func (l *Logger) Logw(level Level, message string, keysAndValues ...interface{}) {
+	if !l.canOutput(level) {
+		return
+	}
+	l.log(level, message, sweetenFields(keysAndValues))
+	l.finish(level)
+}
+
+func (l *Logger) LogCtx(ctx context.Context, level Level, message string, keysAndValues ...interface{}) {
+	if !l.canOutput(level) {
+		return
+	}
+	l.log(level, message, l.contextFields(ctx, keysAndValues))
+	l.finish(level)
+}
+
+// 通过Log系列方法输出Fatal级别日志时, 保持与Fatal方法一致的行为
+func (l *Logger) finish(level Level) {
+	if level == LevelFatal {
+		l.exit()
+	}
+}
+
 func (l *Logger) Panic(args ...interface{}) {
-	if l.canOutput(LevelPanic) {
-		m := pkg.GetMessage("", args)
-		l.log(LevelPanic, m)
-		l.recover(m)
+	if !l.canOutput(LevelPanic) {
+		return
 	}
+	m := pkg.GetMessage("", args)
+	l.log(LevelPanic, m, nil)
 }
 
 func (l *Logger) Panicf(template string, args ...interface{}) {
-	if l.canOutput(LevelPanic) {
-		m := pkg.GetMessage(template, args)
-		l.log(LevelPanic, m)
-		l.recover(m)
+	if !l.canOutput(LevelPanic) {
+		return
+	}
+	m := pkg.GetMessage(template, args)
+	l.log(LevelPanic, m, nil)
+}
+
+func (l *Logger) Panicw(message string, keysAndValues ...interface{}) {
+	if !l.canOutput(LevelPanic) {
+		return
+	}
+	l.log(LevelPanic, message, sweetenFields(keysAndValues))
+}
+
+func (l *Logger) PanicCtx(ctx context.Context, message string, keysAndValues ...interface{}) {
+	if !l.canOutput(LevelPanic) {
+		return
 	}
+	l.log(LevelPanic, message, l.contextFields(ctx, keysAndValues))
 }
 
 func (l *Logger) Fatal(args ...interface{}) {
@@ -203,7 +344,7 @@ func (l *Logger) Fatal(args ...interface{}) {
 		return
 	}
 	m := pkg.GetMessage("", args)
-	l.log(LevelFatal, m)
+	l.log(LevelFatal, m, nil)
 	l.exit()
 }
 
@@ -212,7 +353,23 @@ func (l *Logger) Fatalf(template string, args ...interface{}) {
 		return
 	}
 	m := pkg.GetMessage(template, args)
-	l.log(LevelFatal, m)
+	l.log(LevelFatal, m, nil)
+	l.exit()
+}
+
+func (l *Logger) Fatalw(message string, keysAndValues ...interface{}) {
+	if !l.canOutput(LevelFatal) {
+		return
+	}
+	l.log(LevelFatal, message, sweetenFields(keysAndValues))
+	l.exit()
+}
+
+func (l *Logger) FatalCtx(ctx context.Context, message string, keysAndValues ...interface{}) {
+	if !l.canOutput(LevelFatal) {
+		return
+	}
+	l.log(LevelFatal, message, l.contextFields(ctx, keysAndValues))
 	l.exit()
 }
 
@@ -221,7 +378,7 @@ func (l *Logger) Error(args ...interface{}) {
 		return
 	}
 	m := pkg.GetMessage("", args)
-	l.log(LevelError, m)
+	l.log(LevelError, m, nil)
 }
 
 func (l *Logger) Errorf(template string, args ...interface{}) {
@@ -229,7 +386,21 @@ func (l *Logger) Errorf(template string, args ...interface{}) {
 		return
 	}
 	m := pkg.GetMessage(template, args)
-	l.log(LevelError, m)
+	l.log(LevelError, m, nil)
+}
+
+func (l *Logger) Errorw(message string, keysAndValues ...interface{}) {
+	if !l.canOutput(LevelError) {
+		return
+	}
+	l.log(LevelError, message, sweetenFields(keysAndValues))
+}
+
+func (l *Logger) ErrorCtx(ctx context.Context, message string, keysAndValues ...interface{}) {
+	if !l.canOutput(LevelError) {
+		return
+	}
+	l.log(LevelError, message, l.contextFields(ctx, keysAndValues))
 }
 
 func (l *Logger) Warn(args ...interface{}) {
@@ -237,7 +408,7 @@ func (l *Logger) Warn(args ...interface{}) {
 		return
 	}
 	m := pkg.GetMessage("", args)
-	l.log(LevelWarn, m)
+	l.log(LevelWarn, m, nil)
 }
 
 func (l *Logger) Warnf(template string, args ...interface{}) {
@@ -245,7 +416,21 @@ func (l *Logger) Warnf(template string, args ...interface{}) {
 		return
 	}
 	m := pkg.GetMessage(template, args)
-	l.log(LevelWarn, m)
+	l.log(LevelWarn, m, nil)
+}
+
+func (l *Logger) Warnw(message string, keysAndValues ...interface{}) {
+	if !l.canOutput(LevelWarn) {
+		return
+	}
+	l.log(LevelWarn, message, sweetenFields(keysAndValues))
+}
+
+func (l *Logger) WarnCtx(ctx context.Context, message string, keysAndValues ...interface{}) {
+	if !l.canOutput(LevelWarn) {
+		return
+	}
+	l.log(LevelWarn, message, l.contextFields(ctx, keysAndValues))
 }
 
 func (l *Logger) Info(args ...interface{}) {
@@ -253,7 +438,7 @@ func (l *Logger) Info(args ...interface{}) {
 		return
 	}
 	m := pkg.GetMessage("", args)
-	l.log(LevelInfo, m)
+	l.log(LevelInfo, m, nil)
 }
 
 func (l *Logger) Infof(template string, args ...interface{}) {
@@ -261,7 +446,21 @@ func (l *Logger) Infof(template string, args ...interface{}) {
 		return
 	}
 	m := pkg.GetMessage(template, args)
-	l.log(LevelInfo, m)
+	l.log(LevelInfo, m, nil)
+}
+
+func (l *Logger) Infow(message string, keysAndValues ...interface{}) {
+	if !l.canOutput(LevelInfo) {
+		return
+	}
+	l.log(LevelInfo, message, sweetenFields(keysAndValues))
+}
+
+func (l *Logger) InfoCtx(ctx context.Context, message string, keysAndValues ...interface{}) {
+	if !l.canOutput(LevelInfo) {
+		return
+	}
+	l.log(LevelInfo, message, l.contextFields(ctx, keysAndValues))
 }
 
 func (l *Logger) Debug(args ...interface{}) {
@@ -269,7 +468,7 @@ func (l *Logger) Debug(args ...interface{}) {
 		return
 	}
 	m := pkg.GetMessage("", args)
-	l.log(LevelDebug, m)
+	l.log(LevelDebug, m, nil)
 }
 
 func (l *Logger) Debugf(template string, args ...interface{}) {
@@ -277,5 +476,19 @@ func (l *Logger) Debugf(template string, args ...interface{}) {
 		return
 	}
 	m := pkg.GetMessage(template, args)
-	l.log(LevelDebug, m)
+	l.log(LevelDebug, m, nil)
+}
+
+func (l *Logger) Debugw(message string, keysAndValues ...interface{}) {
+	if !l.canOutput(LevelDebug) {
+		return
+	}
+	l.log(LevelDebug, message, sweetenFields(keysAndValues))
+}
+
+func (l *Logger) DebugCtx(ctx context.Context, message string, keysAndValues ...interface{}) {
+	if !l.canOutput(LevelDebug) {
+		return
+	}
+	l.log(LevelDebug, message, l.contextFields(ctx, keysAndValues))
 }
@@ -1,32 +1,75 @@
 package plogs
 
 import (
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/pyihe/plogs/internal"
+	"github.com/pyihe/plogs/writer"
 )
 
 type Option func(c *Logger)
 
 // LogConfig 配置项
 type LogConfig struct {
-	stdout     bool          // 是否stdin输出
-	fileOption FileOption    // 日志记录方式
-	logLevel   Level         // 需要记录的日志级别
-	maxAge     time.Duration // 日志文件保存最长时间
-	maxSize    int64         // 日志文件大小上限
-	name       string        // 日志来自哪个应用
-	logPath    string        // 日志存储路径
+	console          ConsoleMode        // 终端输出方式
+	stderrLevels     Level              // ConsoleSplit时输出到stderr的级别
+	fileOption       FileOption         // 日志记录方式
+	logLevel         int64              // 需要记录的日志级别, 运行时可修改, 需要原子操作
+	levelMu          sync.Mutex         // 修改namedLevels时加锁
+	namedLevels      atomic.Value       // 按Logger名称单独设置的日志级别: map[string]Level, 写时复制
+	maxAge           time.Duration      // 日志文件保存最长时间
+	maxSize          int64              // 日志文件大小上限
+	maxBackups       int                // 每个目录下切割后的文件最多保留的个数
+	maxTotalSize     int64              // 每个目录下日志文件的总大小上限
+	rotateInterval   time.Duration      // 日志文件按时间切割的间隔
+	rotateLocation   *time.Location     // 按时间切割时对齐所使用的时区
+	rotateName       string             // 切割后的文件名格式
+	fileName         string             // 正在写入的日志文件名
+	compressor       writer.Compressor  // 切割后文件的压缩方式
+	name             string             // 日志来自哪个应用
+	logPath          string             // 日志存储路径
+	extractors       []ContextExtractor // 从context中提取字段
+	callerSkip       int                // 获取调用者时额外跳过的调用栈层数
+	callerMode       CallerMode         // 调用者的输出方式
+	callerFunc       bool               // 是否输出调用者的函数名
+	formatter        Formatter          // 日志编码方式
+	consoleFormatter Formatter          // 终端输出的编码方式, 为nil时使用formatter
+	fileFormatter    Formatter          // 文件的编码方式, 为nil时使用formatter
+	colorMode        ColorMode          // 终端输出的着色方式
+	palette          Palette            // 终端输出的配色
+	writers          []*subscription    // 自定义writer
+	routes           []Route            // 路由规则, 为nil时使用fileOption对应的规则
 }
 
-// WithStdout 设置是否同步输出到标准输出
+// WithStdout 设置是否同步输出到标准输出, 等同于WithConsole(ConsoleStdout)
 func WithStdout(b bool) Option {
 	return func(c *Logger) {
-		c.config.stdout = b
+		if b {
+			c.config.console = ConsoleStdout
+		} else {
+			c.config.console = ConsoleNone
+		}
+	}
+}
+
+// WithConsole 设置终端输出方式: 只输出到stdout、只输出到stderr或者按级别分别输出到stdout与stderr
+func WithConsole(mode ConsoleMode) Option {
+	return func(c *Logger) {
+		if mode.valid() {
+			c.config.console = mode
+		}
 	}
 }
 
-// WithFileOption 设置写文件选项
+// WithStderrLevel 设置ConsoleSplit时输出到stderr的级别, 默认为Error及以上级别
+func WithStderrLevel(levels Level) Option {
+	return func(c *Logger) {
+		c.config.stderrLevels = levels
+	}
+}
+
+// WithFileOption 设置写文件选项, 设置了WithRoutes时不生效
 func WithFileOption(opt FileOption) Option {
 	return func(c *Logger) {
 		if opt.valid() {
@@ -35,14 +78,43 @@ func WithFileOption(opt FileOption) Option {
 	}
 }
 
-// WithLogLevel 日志记录级别: [ LevelFatal | LevelFatal | LevelError | LevelWarn | LevelInfo | LevelDebug ]
+// WithRoutes 通过路由规则决定日志写入哪些目标, 设置后WithFileOption不再生效, 终端输出仍由WithConsole控制;
+// 可以通过WriteByBoth.Routes()等获取写文件选项对应的规则, 在此基础上添加规则:
+//
+//	WithRoutes(
+//		Route{Levels: LevelError | LevelFatal, To: []string{"file:critical"}},
+//		Route{To: []string{DestFile}},
+//		Route{Levels: LevelDebug, To: []string{DestStdout}},
+//	)
+func WithRoutes(routes ...Route) Option {
+	return func(c *Logger) {
+		c.config.routes = append(c.config.routes, routes...)
+	}
+}
+
+// WithLogLevel 日志记录级别: [ LevelPanic | LevelFatal | LevelError | LevelWarn | LevelInfo | LevelDebug | 自定义级别 ]
 func WithLogLevel(level Level) Option {
 	return func(c *Logger) {
-		c.config.logLevel = level
+		c.config.storeLevel(level)
+	}
+}
+
+// WithMinLevel 记录严重程度不低于level的所有级别, 如WithMinLevel(LevelWarn)记录Warn、Error、Fatal、Panic
+func WithMinLevel(level Level) Option {
+	return func(c *Logger) {
+		c.config.storeLevel(AtLeast(level))
 	}
 }
 
-// WithMaxAge 设置日志文件保存最长时间
+// WithNamedLevel 为名称为name的Logger及其子Logger单独设置日志级别, name可以写作"db"或者"db.*",
+// 存在多个匹配时使用名称最长的配置
+func WithNamedLevel(name string, level Level) Option {
+	return func(c *Logger) {
+		c.config.setNamedLevel(name, level)
+	}
+}
+
+// WithMaxAge 设置切割后的日志文件保存最长时间, 超过后删除, 默认不删除
 func WithMaxAge(t time.Duration) Option {
 	return func(c *Logger) {
 		c.config.maxAge = t
@@ -56,6 +128,62 @@ func WithMaxSize(size int64) Option {
 	}
 }
 
+// WithMaxBackups 设置每个日志目录下切割后的文件最多保留的个数, 超过时从最旧的文件开始删除, 默认不限制
+func WithMaxBackups(n int) Option {
+	return func(c *Logger) {
+		c.config.maxBackups = n
+	}
+}
+
+// WithMaxTotalSize 设置每个日志目录下日志文件的总大小上限, 超过时从最旧的切割后的文件开始删除, 默认不限制;
+// 区分级别记录时, 日志目录与各级别的子目录分别计算
+func WithMaxTotalSize(size int64) Option {
+	return func(c *Logger) {
+		c.config.maxTotalSize = size
+	}
+}
+
+// WithRotateInterval 设置日志文件按时间切割的间隔, 切割时间以loc时区的零点为起点对齐:
+// time.Hour为每个整点切割, 24*time.Hour为每天零点切割, loc为nil时使用本地时区;
+// 与WithMaxSize同时设置时, 任意一个条件满足都会切割
+func WithRotateInterval(interval time.Duration, loc *time.Location) Option {
+	return func(c *Logger) {
+		c.config.rotateInterval = interval
+		c.config.rotateLocation = loc
+	}
+}
+
+// WithFileName 设置正在写入的日志文件名, 默认为temp.log, 支持以下占位符:
+//
+//	{app}     应用名
+//	{level}   级别目录中为级别名称, 如error; 日志目录下为all, 其他子目录下为子目录名
+//
+// 如"{app}.{level}.log"在日志目录下为app.all.log, errors目录下为app.error.log.
+// 不支持{pid}: 重启后上一个进程写入的文件名与新的文件名不同, 该文件既不会被切割、压缩, 也不会被保留策略删除
+func WithFileName(pattern string) Option {
+	return func(c *Logger) {
+		if pattern != "" {
+			c.config.fileName = pattern
+		}
+	}
+}
+
+// WithRotateName 设置切割后的文件名格式, 如"{app}.{date}.{seq}.log"生成app.2026-10-18.003.log,
+// 除{app}、{level}(同WithFileName)外支持的占位符见writer.WithRotateName; 切割后的文件名保证不会与已有的文件重复;
+// 保留策略(WithMaxAge等)只删除符合该格式的文件, 多个应用共用日志目录时建议在格式中包含{app}
+func WithRotateName(pattern string) Option {
+	return func(c *Logger) {
+		c.config.rotateName = pattern
+	}
+}
+
+// WithCompressor 设置切割后日志文件的压缩方式, 如writer.GzipCompressor{}, 压缩在后台协程中进行, 默认不压缩
+func WithCompressor(compressor writer.Compressor) Option {
+	return func(c *Logger) {
+		c.config.compressor = compressor
+	}
+}
+
 // WithName 设置app名称
 func WithName(name string) Option {
 	return func(c *Logger) {
@@ -72,11 +200,98 @@ func WithLogPath(filepath string) Option {
 	}
 }
 
-// WithWriter 添加自定义Writer
-func WithWriter(writer ...internal.LogWriter) Option {
+// WithContextExtractor 注册ContextExtractor, InfoCtx等方法会将从context中提取的字段添加到日志中
+func WithContextExtractor(extractors ...ContextExtractor) Option {
+	return func(c *Logger) {
+		for _, extractor := range extractors {
+			if extractor != nil {
+				c.config.extractors = append(c.config.extractors, extractor)
+			}
+		}
+	}
+}
+
+// WithCallerSkip 获取调用者时额外跳过skip层调用栈, 用于将plogs封装在自己的日志函数中时
+func WithCallerSkip(skip int) Option {
+	return func(c *Logger) {
+		c.config.callerSkip = skip
+	}
+}
+
+// WithCallerMode 设置调用者的输出方式, 默认为CallerFull
+func WithCallerMode(mode CallerMode) Option {
+	return func(c *Logger) {
+		if mode.valid() {
+			c.config.callerMode = mode
+		}
+	}
+}
+
+// WithCallerFunction 设置是否在调用者后输出函数名
+func WithCallerFunction(b bool) Option {
+	return func(c *Logger) {
+		c.config.callerFunc = b
+	}
+}
+
+// WithFormatter 设置日志默认的编码方式, 默认为TextFormatter; 终端、文件以及自定义writer可以单独指定编码方式
+func WithFormatter(f Formatter) Option {
+	return func(c *Logger) {
+		if f != nil {
+			c.config.formatter = f
+		}
+	}
+}
+
+// WithConsoleFormatter 单独设置终端输出的编码方式
+func WithConsoleFormatter(f Formatter) Option {
+	return func(c *Logger) {
+		c.config.consoleFormatter = f
+	}
+}
+
+// WithFileFormatter 单独设置写入日志文件的编码方式
+func WithFileFormatter(f Formatter) Option {
+	return func(c *Logger) {
+		c.config.fileFormatter = f
+	}
+}
+
+// WithColor 设置终端输出的着色方式, 默认为ColorAuto; 写入文件的日志不会着色
+func WithColor(mode ColorMode) Option {
+	return func(c *Logger) {
+		if mode.valid() {
+			c.config.colorMode = mode
+		}
+	}
+}
+
+// WithPalette 设置终端输出各级别使用的颜色, 未设置的级别不着色
+func WithPalette(palette Palette) Option {
+	return func(c *Logger) {
+		if palette != nil {
+			c.config.palette = palette
+		}
+	}
+}
+
+// WithWriter 添加自定义Writer, 接收所有级别的日志; writer的名称不能为空、不能以"plogs."开头且不能重复
+func WithWriter(writers ...writer.LogWriter) Option {
+	return func(c *Logger) {
+		for _, w := range writers {
+			if w != nil {
+				c.config.writers = append(c.config.writers, &subscription{writer: w, levels: levelAll})
+			}
+		}
+	}
+}
+
+// WithLevelWriter 添加只接收levels中各级别日志的自定义Writer, filter不为nil时只接收filter返回true的日志;
+// levels为0时只接收WithRoutes中指向该writer的日志
+func WithLevelWriter(w writer.LogWriter, levels Level, filter EntryFilter) Option {
 	return func(c *Logger) {
-		for _, w := range writer {
-			c.writer.AddWriter(w)
+		if w != nil {
+			c.config.writers = append(c.config.writers, &subscription{writer: w, levels: levels, filter: filter})
 		}
 	}
 }
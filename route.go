@@ -0,0 +1,136 @@
+package plogs
+
+import (
+	"strings"
+)
+
+// 路由目标
+const (
+	DestStdout   = "stdout"       // 终端stdout
+	DestStderr   = "stderr"       // 终端stderr
+	DestFile     = "file"         // 日志目录下的文件
+	DestLevelDir = "file:{level}" // 日志目录下各级别对应子目录(如errors)中的文件
+)
+
+// 写入日志目录下子目录的目标前缀, 如"file:critical"
+const destFilePrefix = DestFile + ":"
+
+// Route 路由规则, 日志满足规则的所有条件时写入To中的每一个目标, 目标可以是:
+//
+//	"stdout"、"stderr"    终端, 与WithConsole的输出不会重复
+//	"file"                日志目录下的文件
+//	"file:critical"       日志目录下critical子目录中的文件
+//	"file:{level}"        日志目录下各级别对应子目录中的文件, 如errors、infos
+//	其他                  通过WithWriter等添加的自定义writer的名称, writer不存在时忽略
+//
+// 多条规则指向同一个目标时, 每条日志也只会写入一次
+type Route struct {
+	Levels Level             // 匹配的级别, 为0时匹配所有级别
+	Logger string            // 匹配名称为Logger的Logger及其子Logger, 可以写作"db"或者"db.*", 为空时匹配所有Logger
+	Fields map[string]string // 日志中包含这些字段且值(文本形式)全部相等时才匹配
+	To     []string          // 目标
+}
+
+// route 初始化时由Route转换而来
+type route struct {
+	levels   Level    // 匹配的级别
+	logger   string   // 去掉".*"后的Logger名称
+	fields   []Field  // 需要匹配的字段
+	targets  []string // 目标writer的名称
+	dirs     []string // 需要创建文件writer的子目录
+	levelDir bool     // 是否写入各级别对应的子目录
+}
+
+// Routes 返回写文件选项对应的路由规则, 可以在此基础上添加规则后传给WithRoutes
+func (w FileOption) Routes() []Route {
+	switch w {
+	case WriteByLevelSeparated:
+		return []Route{{To: []string{DestLevelDir}}}
+	case WriteByLevelMerged:
+		return []Route{{To: []string{DestFile}}}
+	case WriteByBoth:
+		return []Route{{To: []string{DestFile, DestLevelDir}}}
+	}
+	return nil
+}
+
+func newRoute(r Route) *route {
+	rt := &route{
+		levels: r.Levels,
+		logger: strings.TrimSuffix(r.Logger, ".*"),
+	}
+	if rt.levels == 0 {
+		rt.levels = levelAll
+	}
+	for key, value := range r.Fields {
+		rt.fields = append(rt.fields, String(key, value))
+	}
+	for _, dest := range r.To {
+		switch {
+		case dest == DestStdout:
+			rt.targets = append(rt.targets, stdoutName)
+		case dest == DestStderr:
+			rt.targets = append(rt.targets, stderrName)
+		case dest == DestLevelDir:
+			rt.levelDir = true
+		case dest == DestFile || strings.HasPrefix(dest, destFilePrefix):
+			dir := strings.TrimPrefix(strings.TrimPrefix(dest, DestFile), ":")
+			rt.dirs = append(rt.dirs, dir)
+			rt.targets = append(rt.targets, fileWriterName(dir))
+		case dest != "":
+			rt.targets = append(rt.targets, strings.ToLower(dest))
+		}
+	}
+	return rt
+}
+
+func (r *route) match(e *Entry) bool {
+	if r.levels&e.Level != e.Level {
+		return false
+	}
+	if r.logger != "" && e.Logger != r.logger && !strings.HasPrefix(e.Logger, r.logger+".") {
+		return false
+	}
+	for _, want := range r.fields {
+		if !hasField(e.Fields, want.Key, want.str) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasField(fields []Field, key, value string) bool {
+	for _, f := range fields {
+		if f.Key == key && f.valueString() == value {
+			return true
+		}
+	}
+	return false
+}
+
+// 是否存在写入name的路由规则
+func (l *Logger) routesTo(name string) bool {
+	for _, r := range l.routes {
+		for _, target := range r.targets {
+			if target == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// 获取日志需要输出到的writer
+func (l *Logger) outputs(e *Entry) []string {
+	outputs := make([]string, 0, 2)
+	for _, r := range l.routes {
+		if !r.match(e) {
+			continue
+		}
+		outputs = append(outputs, r.targets...)
+		if r.levelDir {
+			outputs = append(outputs, fileWriterName(subPath(e.Level)))
+		}
+	}
+	return outputs
+}
@@ -0,0 +1,50 @@
+package plogs
+
+import "testing"
+
+func TestRouteMatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		route Route
+		entry Entry
+		want  bool
+	}{
+		{"all levels by default", Route{}, Entry{Level: LevelDebug}, true},
+		{"level in mask", Route{Levels: LevelError | LevelFatal}, Entry{Level: LevelError}, true},
+		{"level not in mask", Route{Levels: LevelError | LevelFatal}, Entry{Level: LevelWarn}, false},
+		{"logger exact", Route{Logger: "db"}, Entry{Level: LevelInfo, Logger: "db"}, true},
+		{"logger child", Route{Logger: "db"}, Entry{Level: LevelInfo, Logger: "db.pool"}, true},
+		{"logger wildcard", Route{Logger: "db.*"}, Entry{Level: LevelInfo, Logger: "db.pool"}, true},
+		{"logger wildcard parent", Route{Logger: "db.*"}, Entry{Level: LevelInfo, Logger: "db"}, true},
+		{"logger sharing prefix", Route{Logger: "db"}, Entry{Level: LevelInfo, Logger: "dbx"}, false},
+		{"logger unnamed", Route{Logger: "db"}, Entry{Level: LevelInfo}, false},
+		{"field equal", Route{Fields: map[string]string{"user": "42"}}, Entry{Level: LevelInfo, Fields: []Field{Int("user", 42)}}, true},
+		{"field differs", Route{Fields: map[string]string{"user": "42"}}, Entry{Level: LevelInfo, Fields: []Field{Int("user", 7)}}, false},
+		{"field missing", Route{Fields: map[string]string{"user": "42"}}, Entry{Level: LevelInfo}, false},
+		{"all fields required", Route{Fields: map[string]string{"user": "42", "env": "prod"}}, Entry{Level: LevelInfo, Fields: []Field{Int("user", 42)}}, false},
+		{"all conditions", Route{Levels: LevelError, Logger: "db", Fields: map[string]string{"env": "prod"}}, Entry{Level: LevelError, Logger: "db.pool", Fields: []Field{String("env", "prod")}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newRoute(tt.route).match(&tt.entry); got != tt.want {
+				t.Errorf("match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouteTargets(t *testing.T) {
+	r := newRoute(Route{To: []string{DestStdout, DestFile, "file:critical", DestLevelDir, "Kafka"}})
+	want := []string{stdoutName, fileWriterName(""), fileWriterName("critical"), "kafka"}
+	if len(r.targets) != len(want) {
+		t.Fatalf("targets = %v, want %v", r.targets, want)
+	}
+	for i := range want {
+		if r.targets[i] != want[i] {
+			t.Errorf("targets[%d] = %q, want %q", i, r.targets[i], want[i])
+		}
+	}
+	if !r.levelDir {
+		t.Error("levelDir = false, want true")
+	}
+}
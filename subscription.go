@@ -0,0 +1,73 @@
+package plogs
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pyihe/plogs/writer"
+)
+
+// 内置writer(终端、日志文件)名称的前缀, 自定义writer不能使用, 避免与内置writer冲突
+const builtinPrefix = "plogs."
+
+var ErrInvalidWriterName = errors.New("writer name is empty or starts with \"plogs.\"")
+
+// EntryFilter 返回false时该条日志不会写入对应的writer
+type EntryFilter func(e *Entry) bool
+
+// subscription 自定义writer订阅的日志
+type subscription struct {
+	name   string           // 小写的writer名称
+	writer writer.LogWriter // writer
+	levels Level            // 订阅的级别
+	filter EntryFilter      // 为nil时接收订阅级别的所有日志
+}
+
+func (s *subscription) match(e *Entry) bool {
+	if s.levels&e.Level != e.Level {
+		return false
+	}
+	return s.filter == nil || s.filter(e)
+}
+
+// AddWriter 运行时添加自定义writer, writer接收levels中各级别的日志, filter不为nil时只接收filter返回true的日志;
+// writer的名称为空、以"plogs."开头或者与已有writer重复时返回错误
+func (l *Logger) AddWriter(w writer.LogWriter, levels Level, filter EntryFilter) error {
+	l.writerMu.Lock()
+	defer l.writerMu.Unlock()
+	if atomic.LoadInt32(&l.closed) == 1 {
+		return writer.ErrClosed
+	}
+	return l.subscribe(&subscription{writer: w, levels: levels, filter: filter}, l.started)
+}
+
+// 调用方需要持有writerMu或者处于Logger初始化阶段
+func (l *Logger) subscribe(sub *subscription, start bool) error {
+	sub.name = strings.ToLower(sub.writer.Name())
+	if sub.name == "" || strings.HasPrefix(sub.name, builtinPrefix) {
+		return ErrInvalidWriterName
+	}
+	if l.writer.Exist(sub.name) {
+		return writer.ErrDuplicateName
+	}
+	if start {
+		sub.writer.Start()
+	}
+	if err := l.writer.AddWriter(sub.writer); err != nil {
+		return err
+	}
+
+	// 写时复制, 写日志时无需加锁
+	subs := l.subscriptions()
+	next := make([]*subscription, 0, len(subs)+1)
+	next = append(next, subs...)
+	next = append(next, sub)
+	l.subs.Store(next)
+	return nil
+}
+
+func (l *Logger) subscriptions() []*subscription {
+	subs, _ := l.subs.Load().([]*subscription)
+	return subs
+}
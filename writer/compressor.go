@@ -0,0 +1,189 @@
+package writer
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Compressor 压缩切割后的日志文件, 压缩在单独的协程中进行, 不会阻塞日志的写入
+type Compressor interface {
+	// Ext 返回压缩后追加在文件名后的扩展名, 如".gz"
+	Ext() string
+	// Compress 将src压缩后写入dst
+	Compress(dst io.Writer, src io.Reader) error
+}
+
+// GzipCompressor 使用gzip压缩, 压缩后的文件名如2026_10_18_15_04_05.log.gz
+type GzipCompressor struct {
+	Level int // 压缩级别, 见compress/gzip, 为0时使用gzip.DefaultCompression
+}
+
+func (c GzipCompressor) Ext() string {
+	return ".gz"
+}
+
+func (c GzipCompressor) Compress(dst io.Writer, src io.Reader) error {
+	level := c.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	zw, err := gzip.NewWriterLevel(dst, level)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(zw, src); err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+// compressQueue 等待压缩的文件, 添加时不会阻塞
+type compressQueue struct {
+	compressor Compressor
+	wg         sync.WaitGroup      // 等待压缩协程退出
+	mu         sync.Mutex          // 保护files、pending与current
+	files      []string            // 等待压缩的文件
+	pending    map[string]struct{} // 等待压缩以及正在压缩的文件
+	current    string              // 正在压缩的文件
+	signal     chan struct{}       // 有新的文件需要压缩
+	done       chan struct{}       // 关闭信号
+}
+
+func newCompressQueue(c Compressor) *compressQueue {
+	return &compressQueue{
+		compressor: c,
+		pending:    make(map[string]struct{}),
+		signal:     make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+}
+
+func (q *compressQueue) push(file string) {
+	q.mu.Lock()
+	if _, exist := q.pending[file]; exist {
+		q.mu.Unlock()
+		return
+	}
+	q.files = append(q.files, file)
+	q.pending[file] = struct{}{}
+	q.mu.Unlock()
+
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+}
+
+// 取出下一个需要压缩的文件, 并标记为正在压缩
+func (q *compressQueue) next() (file string, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.files) == 0 {
+		return "", false
+	}
+	file, q.files = q.files[0], q.files[1:]
+	q.current = file
+	return file, true
+}
+
+// 从队列中移除等待压缩的文件, 用于保留策略删除该文件; 文件正在压缩时返回false
+func (q *compressQueue) cancel(file string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if file == q.current {
+		return false
+	}
+	for i, f := range q.files {
+		if f == file {
+			q.files = append(q.files[:i], q.files[i+1:]...)
+			break
+		}
+	}
+	delete(q.pending, file)
+	return true
+}
+
+// 依次压缩队列中的文件
+func (q *compressQueue) drain() {
+	for file, ok := q.next(); ok; file, ok = q.next() {
+		q.compress(file)
+	}
+}
+
+func (q *compressQueue) start() {
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		for {
+			select {
+			case <-q.signal:
+				q.drain()
+			case <-q.done:
+				return
+			}
+		}
+	}()
+}
+
+// 等待压缩协程退出后, 压缩剩余的文件
+func (q *compressQueue) stop() {
+	close(q.done)
+	q.wg.Wait()
+	q.drain()
+}
+
+// 文件是否等待压缩或者正在压缩
+func (q *compressQueue) queued(file string) bool {
+	q.mu.Lock()
+	_, exist := q.pending[file]
+	q.mu.Unlock()
+	return exist
+}
+
+func (q *compressQueue) compress(file string) {
+	if err := compressFile(q.compressor, file); err != nil {
+		fmt.Fprintf(os.Stderr, "plogs: compress %s failed: %v\n", file, err)
+	}
+	q.mu.Lock()
+	delete(q.pending, file)
+	q.current = ""
+	q.mu.Unlock()
+}
+
+// 先写入临时文件, 完成后再重命名为压缩后的文件名并删除原文件, 保证不会出现不完整的压缩文件
+func compressFile(c Compressor, file string) error {
+	src, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	target := file + c.Ext()
+	tmp := target + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if err = c.Compress(dst, src); err == nil {
+		err = dst.Sync()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	// 保留原文件的修改时间, 保证保留策略按照日志的时间先后删除
+	if info, statErr := src.Stat(); err == nil && statErr == nil {
+		os.Chtimes(tmp, info.ModTime(), info.ModTime())
+	}
+	if err == nil {
+		err = os.Rename(tmp, target)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Remove(file)
+}
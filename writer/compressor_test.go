@@ -0,0 +1,105 @@
+package writer
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 启动时压缩上次运行遗留的未压缩文件
+func TestCompressLeftoverBackups(t *testing.T) {
+	dir := t.TempDir()
+	leftover := filepath.Join(dir, "2026_10_18_15_04_05.log")
+	if err := os.WriteFile(leftover, []byte("line\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := NewFileWriter(dir, "temp.log", WithCompressor(GzipCompressor{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Start()
+	w.Stop()
+
+	if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+		t.Errorf("leftover %s not removed after compression: %v", leftover, err)
+	}
+	f, err := os.Open(leftover + ".gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(zr)
+	if string(data) != "line\n" {
+		t.Errorf("decompressed = %q, want %q", data, "line\n")
+	}
+}
+
+// 等待压缩的文件计入保留策略, 删除时从压缩队列中移除
+func TestRetentionCancelsQueued(t *testing.T) {
+	dir := t.TempDir()
+	touch(t, dir, "2026_10_18_15_04_05.log", "2026_10_18_15_04_06.log")
+	older := filepath.Join(dir, "2026_10_18_15_04_05.log")
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(older, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := NewFileWriter(dir, "temp.log", WithCompressor(GzipCompressor{}), WithMaxBackups(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw := w.(*fileWriter)
+	defer fw.file.Close()
+
+	fw.compress.push(older)
+	if backups := fw.backups(); len(backups) != 2 {
+		t.Fatalf("backups() = %v, want queued files counted", backups)
+	}
+	fw.checkLife()
+	if fw.compress.queued(older) {
+		t.Errorf("%s still queued after retention removed it", older)
+	}
+	if _, err := os.Stat(older); !os.IsNotExist(err) {
+		t.Errorf("%s not removed: %v", older, err)
+	}
+}
+
+// 压缩跟不上切割速度时, Stop之后保留的文件数量仍然不超过maxBackups
+func TestRetentionWithCompressorAfterStop(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewFileWriter(dir, "temp.log", WithMaxSize(2000), WithMaxBackups(3), WithCompressor(GzipCompressor{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Start()
+	line := []byte(strings.Repeat("x", 99) + "\n")
+	for i := 0; i < 4000; i++ {
+		w.Write(line)
+	}
+	w.Stop()
+
+	entries, _ := os.ReadDir(dir)
+	var backups []string
+	for _, e := range entries {
+		if e.Name() != "temp.log" {
+			backups = append(backups, e.Name())
+		}
+	}
+	if len(backups) != 3 {
+		t.Errorf("backups after Stop = %v, want 3", backups)
+	}
+	for _, name := range backups {
+		if !strings.HasSuffix(name, ".log.gz") {
+			t.Errorf("backup %s not compressed", name)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package writer
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pyihe/plogs/pkg"
+)
+
+// Option 文件writer的配置项
+type Option func(fw *fileWriter)
+
+type fileWriter struct {
+	closed      int32          // writer是否已关闭
+	name        string         // writer名称
+	wg          sync.WaitGroup // 等待写协程退出
+	done        chan struct{}  // 关闭信号
+	filePath    string         // 文件保存路径
+	fileName    string         // 文件名
+	maxSize     int64          // 文件大小上限
+	currentSize int64          // 当前文件大小（记录当前已经写入的字节数）
+	maxAge      time.Duration  // 文件保存最长时间
+	maxBackups  int            // 切割后的文件最多保留的个数
+	maxTotal    int64          // 目录下日志文件的总大小上限
+	interval    time.Duration  // 按时间切割的间隔
+	location    *time.Location // 按时间切割时对齐所使用的时区
+	rotateAt    time.Time      // 下一次按时间切割的时间
+	rotateName  rotateName     // 切割后的文件名
+	since       time.Time      // 当前文件中日志的起始时间
+	compress    *compressQueue // 切割后的文件压缩队列, 为nil时不压缩
+	file        *os.File       // 文件句柄
+	writeBuffer chan []byte    // 写缓存
+}
+
+// WithName 设置writer的名称, 默认为"file"
+func WithName(name string) Option {
+	return func(fw *fileWriter) {
+		if name != "" {
+			fw.name = name
+		}
+	}
+}
+
+// WithMaxSize 设置文件大小上限, 超过后切割文件, 默认不切割
+func WithMaxSize(size int64) Option {
+	return func(fw *fileWriter) {
+		fw.maxSize = size
+	}
+}
+
+// WithMaxAge 设置切割后的文件保存最长时间, 默认不删除
+func WithMaxAge(t time.Duration) Option {
+	return func(fw *fileWriter) {
+		fw.maxAge = t
+	}
+}
+
+// WithMaxBackups 设置切割后的文件最多保留的个数, 超过时从最旧的文件开始删除, 默认不限制
+func WithMaxBackups(n int) Option {
+	return func(fw *fileWriter) {
+		fw.maxBackups = n
+	}
+}
+
+// WithMaxTotalSize 设置目录下日志文件(包括正在写入的文件)的总大小上限, 超过时从最旧的切割后的文件开始删除, 默认不限制
+func WithMaxTotalSize(size int64) Option {
+	return func(fw *fileWriter) {
+		fw.maxTotal = size
+	}
+}
+
+// WithRotateInterval 设置按时间切割的间隔, 切割时间以loc时区的零点为起点对齐:
+// time.Hour为每个整点切割, 24*time.Hour为每天零点切割; loc为nil时使用本地时区.
+// 可以与WithMaxSize同时使用, 任意一个条件满足时都会切割
+func WithRotateInterval(interval time.Duration, loc *time.Location) Option {
+	return func(fw *fileWriter) {
+		if loc == nil {
+			loc = time.Local
+		}
+		fw.interval = interval
+		fw.location = loc
+	}
+}
+
+// WithRotateName 设置切割后的文件名格式, 默认为DefaultRotateName, 支持以下占位符:
+//
+//	{host}          主机名
+//	{pid}           进程id
+//	{date}          文件中日志的起始日期, 格式为2006-01-02, 也可以通过{date:2006-01-02T15}指定格式
+//	{seq}           从001开始的序号, 同一目录下{seq}以外部分相同的文件依次递增
+//
+// 如"app.{date}.{seq}.log"生成app.2026-10-18.001.log; 格式中没有{seq}时, 重名的文件会在扩展名前添加序号,
+// 因此切割后的文件不会覆盖已有的文件. 不以.log结尾时会自动添加.log; 保留策略只处理符合该格式的文件,
+// 多个应用共用同一目录时, 应通过不同的格式(如"{app}.{date}.{seq}")区分各自的文件
+func WithRotateName(pattern string) Option {
+	return func(fw *fileWriter) {
+		if pattern == "" {
+			return
+		}
+		if !strings.HasSuffix(pattern, ".log") {
+			pattern += ".log"
+		}
+		fw.rotateName.pattern = pattern
+	}
+}
+
+// WithCompressor 设置切割后文件的压缩方式, 如GzipCompressor{}, 默认不压缩
+func WithCompressor(c Compressor) Option {
+	return func(fw *fileWriter) {
+		if c != nil {
+			fw.compress = newCompressQueue(c)
+			fw.rotateName.ext = c.Ext()
+		}
+	}
+}
+
+// NewFileWriter 创建异步写入filePath目录下fileName文件的writer, 目录不存在时会自动创建
+func NewFileWriter(filePath, fileName string, opts ...Option) (LogWriter, error) {
+	if err := pkg.MakeDir(filePath); err != nil {
+		return nil, err
+	}
+	// 打开日志文件句柄
+	name := pkg.JoinPathName(filePath, fileName)
+	file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	fw := &fileWriter{
+		name:        "file",
+		done:        make(chan struct{}),
+		filePath:    filePath,
+		fileName:    fileName,
+		currentSize: stat.Size(),
+		rotateName:  rotateName{pattern: DefaultRotateName, location: time.Local},
+		since:       time.Now(),
+		file:        file,
+		writeBuffer: make(chan []byte, 1<<10),
+	}
+	for _, op := range opts {
+		op(fw)
+	}
+	fw.rotateName.compile()
+	// 已有的文件从其最后修改时间开始计算, 保证重启后上一个周期的日志也能按时切割
+	if stat.Size() > 0 {
+		fw.since = stat.ModTime()
+	}
+	if fw.interval > 0 {
+		fw.rotateName.location = fw.location
+		fw.rotateAt = nextRotateTime(fw.since, fw.interval, fw.location)
+	}
+	return fw, nil
+}
+
+func (fw *fileWriter) Name() string {
+	return fw.name
+}
+
+func (fw *fileWriter) Write(b []byte) (n int, err error) {
+	// 如果已经关闭
+	if atomic.LoadInt32(&fw.closed) == 1 {
+		return 0, ErrClosed
+	}
+	select {
+	case fw.writeBuffer <- b:
+		return len(b), nil
+	case <-fw.done:
+		return 0, ErrClosed
+	}
+}
+
+func (fw *fileWriter) Stop() {
+	if !atomic.CompareAndSwapInt32(&fw.closed, 0, 1) {
+		return
+	}
+	close(fw.done)
+	fw.wg.Wait()
+
+	fw.clean()
+}
+
+func (fw *fileWriter) Start() {
+	if fw.compress != nil {
+		fw.compressBackups()
+		fw.compress.start()
+	}
+	fw.wg.Add(1)
+	go func() {
+		defer fw.wg.Done()
+
+		var ticker *time.Ticker
+		var tickC <-chan time.Time
+		if duration := fw.checkLife(); duration > 0 {
+			ticker = time.NewTicker(duration)
+			tickC = ticker.C
+		}
+		var timer *time.Timer
+		var rotateC <-chan time.Time
+		if fw.interval > 0 {
+			timer = time.NewTimer(time.Until(fw.rotateAt))
+			rotateC = timer.C
+		}
+		for {
+			select {
+			case <-fw.done: // 响应最上层调用的Close
+				if ticker != nil {
+					ticker.Stop()
+				}
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+
+			case msg := <-fw.writeBuffer: // 写入文件
+				fw.writeToFile(msg)
+				fw.rotate()
+
+			case now := <-rotateC: // 按时间切割
+				fw.rotateOnTime(now)
+				timer.Reset(time.Until(fw.rotateAt))
+
+			case <-tickC: // 按照保留策略删除目录下切割后的文件
+				fw.checkLife()
+			}
+		}
+	}()
+}
+
+// 写协程退出后, 需要将通道内剩余的日志打入文件中
+func (fw *fileWriter) clean() {
+	for len(fw.writeBuffer) > 0 {
+		fw.writeToFile(<-fw.writeBuffer)
+		fw.rotate()
+	}
+	// 压缩完剩余的文件后再执行保留策略, 保证最终保留的文件符合限制
+	if fw.compress != nil {
+		fw.compress.stop()
+	}
+	fw.checkLife()
+
+	fw.file.Close()
+}
+
+func (fw *fileWriter) writeToFile(msg ...[]byte) {
+	for _, m := range msg {
+		// 记录到文件中
+		n, _ := fw.file.Write(m)
+		// 统计当前已经写入文件的字节数
+		fw.currentSize += int64(n)
+	}
+}
+
+func (fw *fileWriter) rotate() {
+	if fw.maxSize <= 0 || fw.currentSize < fw.maxSize {
+		return
+	}
+	// 如果写入字节数已经超过最大字节数，则需要切割文件
+	fw.rotateFile()
+}
+
+func (fw *fileWriter) rotateFile() {
+	// 同步句柄数据到硬盘
+	fw.file.Sync()
+
+	// 关闭句柄
+	fw.file.Close()
+
+	// 重命名
+	oldName := fw.file.Name()
+	newName := pkg.JoinPathName(fw.filePath, fw.rotateName.next(fw.filePath, fw.since))
+	if err := os.Rename(oldName, newName); err == nil && fw.compress != nil {
+		fw.compress.push(newName)
+	}
+
+	// 重置size和句柄
+	fw.currentSize = 0
+	fw.since = time.Now()
+	fw.file, _ = os.OpenFile(oldName, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+
+	// 切割后文件数量与总大小发生了变化
+	fw.checkLife()
+}
+
+// 到达切割时间, 没有内容的文件不切割, 但新的周期从now开始, 保证之后的文件以其中日志的起始时间命名
+func (fw *fileWriter) rotateOnTime(now time.Time) {
+	if fw.currentSize > 0 {
+		fw.rotateFile()
+	} else {
+		fw.since = now
+	}
+	fw.rotateAt = nextRotateTime(now, fw.interval, fw.location)
+}
+
+// 下一次按时间切割的时间, 以loc时区当天零点为起点按interval对齐, 且不会跨过下一个零点;
+// interval超过一天时按整天计算
+func nextRotateTime(now time.Time, interval time.Duration, loc *time.Location) time.Time {
+	const day = 24 * time.Hour
+
+	now = now.In(loc)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if interval >= day {
+		return midnight.AddDate(0, 0, int(interval/day))
+	}
+	next := midnight.Add((now.Sub(midnight)/interval + 1) * interval)
+	if tomorrow := midnight.AddDate(0, 0, 1); next.After(tomorrow) {
+		next = tomorrow
+	}
+	return next
+}
+
+// 是否为该writer切割后的文件, 包括压缩后的文件; 同一目录下其他应用的日志文件不会被当作切割后的文件
+func (fw *fileWriter) isBackup(name string) bool {
+	if name == fw.fileName {
+		return false
+	}
+	return fw.rotateName.match(name)
+}
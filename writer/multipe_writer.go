@@ -0,0 +1,93 @@
+package writer
+
+import (
+	"sync"
+
+	"github.com/pyihe/go-pkg/strings"
+)
+
+// MultipeWriters 按名称管理多个LogWriter, 名称不区分大小写
+type MultipeWriters struct {
+	mu      sync.RWMutex         // 运行时可能会添加writer
+	writers map[string]LogWriter // writers
+}
+
+func NewMultipeWriters() *MultipeWriters {
+	mw := &MultipeWriters{
+		writers: make(map[string]LogWriter),
+	}
+	return mw
+}
+
+// AddWriter 添加writer, 已经存在同名的writer时返回ErrDuplicateName, 不会覆盖已有的writer
+func (m *MultipeWriters) AddWriter(writer LogWriter) error {
+	if writer == nil {
+		return nil
+	}
+	name := strings.ToLower(writer.Name())
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exist := m.writers[name]; exist {
+		return ErrDuplicateName
+	}
+	m.writers[name] = writer
+	return nil
+}
+
+func (m *MultipeWriters) Get(name string) (LogWriter, bool) {
+	m.mu.RLock()
+	writer, exist := m.writers[strings.ToLower(name)]
+	m.mu.RUnlock()
+	return writer, exist
+}
+
+func (m *MultipeWriters) Exist(name string) bool {
+	m.mu.RLock()
+	_, exist := m.writers[strings.ToLower(name)]
+	m.mu.RUnlock()
+	return exist
+}
+
+func (m *MultipeWriters) WriteTo(b []byte, names ...string) (n int, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, name := range names {
+		writer, exist := m.writers[strings.ToLower(name)]
+		if exist {
+			n, err = writer.Write(b)
+		}
+	}
+	return
+}
+
+func (m *MultipeWriters) Write(b []byte) (n int, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, w := range m.writers {
+		n, err = w.Write(b)
+	}
+	return
+}
+
+func (m *MultipeWriters) Start() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, w := range m.writers {
+		w.Start()
+	}
+}
+
+func (m *MultipeWriters) Stop() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, w := range m.writers {
+		w.Stop()
+	}
+}
+
+func (m *MultipeWriters) Count() (n int) {
+	m.mu.RLock()
+	n = len(m.writers)
+	m.mu.RUnlock()
+	return
+}
@@ -0,0 +1,92 @@
+package writer
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pyihe/plogs/pkg"
+)
+
+// backup 切割后的文件
+type backup struct {
+	path    string    // 路径
+	size    int64     // 大小
+	modTime time.Time // 最后修改时间
+}
+
+// 按照保留策略(maxAge、maxBackups、maxTotal)删除writer所在目录下切割后的文件, 不包括子目录;
+// 返回下一次检查的间隔, 没有设置任何保留策略时返回0
+func (fw *fileWriter) checkLife() time.Duration {
+	if fw.maxAge <= 0 && fw.maxBackups <= 0 && fw.maxTotal <= 0 {
+		return 0
+	}
+
+	const duration = 30 * time.Second
+
+	backups := fw.backups()
+	// 从最新的文件开始, 超过个数或者总大小上限后, 更旧的文件全部删除
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].modTime.After(backups[j].modTime)
+	})
+	var now = time.Now()
+	var total = fw.currentSize
+	var exceeded bool
+	for i, b := range backups {
+		total += b.size
+		if fw.maxBackups > 0 && i >= fw.maxBackups {
+			exceeded = true
+		}
+		if fw.maxTotal > 0 && total > fw.maxTotal {
+			exceeded = true
+		}
+		if exceeded || (fw.maxAge > 0 && now.Sub(b.modTime) >= fw.maxAge) {
+			fw.removeBackup(b.path)
+		}
+	}
+	return duration
+}
+
+// 等待压缩的文件同样计入保留策略, 删除前先从压缩队列中移除; 正在压缩的文件等压缩完成后的下一次检查再删除
+func (fw *fileWriter) removeBackup(path string) {
+	if fw.compress != nil && fw.compress.queued(path) && !fw.compress.cancel(path) {
+		return
+	}
+	os.Remove(path)
+}
+
+// 目录下切割后的文件, 包括压缩后的文件以及等待压缩的文件
+func (fw *fileWriter) backups() []backup {
+	entries, err := os.ReadDir(fw.filePath)
+	if err != nil {
+		return nil
+	}
+	backups := make([]backup, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !fw.isBackup(entry.Name()) {
+			continue
+		}
+		path := pkg.JoinPathName(fw.filePath, entry.Name())
+		// 文件可能已经被压缩协程删除
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{
+			path:    path,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+	return backups
+}
+
+// 压缩上次运行时没有来得及压缩的文件
+func (fw *fileWriter) compressBackups() {
+	for _, b := range fw.backups() {
+		if strings.HasSuffix(b.path, ".log") {
+			fw.compress.push(b.path)
+		}
+	}
+}
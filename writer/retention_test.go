@@ -0,0 +1,62 @@
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRotateNameMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		ext     string
+		name    string
+		want    bool
+	}{
+		{DefaultRotateName, "", "2026_10_18_15_04_05.log", true},
+		{DefaultRotateName, "", "2026_10_18_15_04_05.003.log", true},
+		{DefaultRotateName, "", "other.app.log", false},
+		{DefaultRotateName, "", "2026_10_18_15_04_05.log.gz", false},
+		{DefaultRotateName, ".gz", "2026_10_18_15_04_05.log.gz", true},
+		{"app.{date}.{seq}.log", ".gz", "app.2026-10-18.003.log.gz", true},
+		{"app.{date}.{seq}.log", "", "app.2026-10-18.log", false},
+		{"app.{date}.{seq}.log", "", "other.2026-10-18.003.log", false},
+		{"app-{host}-{pid}.{date:2006-01-02T15}.log", "", "app-vm-1234.2026-10-18T07.log", true},
+		{"app-{host}-{pid}.{date:2006-01-02T15}.log", "", "app-vm-1234.log", false},
+		{"{date:Jan_02}.log", "", "Oct_18.log", true},
+	}
+	for _, tt := range tests {
+		r := &rotateName{pattern: tt.pattern, ext: tt.ext}
+		r.compile()
+		if got := r.match(tt.name); got != tt.want {
+			t.Errorf("pattern %q ext %q: match(%q) = %v, want %v", tt.pattern, tt.ext, tt.name, got, tt.want)
+		}
+	}
+}
+
+// 保留策略不能删除同一目录下其他应用的日志文件
+func TestRetentionKeepsForeignFiles(t *testing.T) {
+	dir := t.TempDir()
+	foreign := []string{"other.app.log", "other.2026-10-18.001.log", "notes.txt"}
+	touch(t, dir, foreign...)
+
+	w, err := NewFileWriter(dir, "mine.log", WithRotateName("mine.{date}.{seq}"), WithMaxSize(10), WithMaxBackups(1), WithMaxTotalSize(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Start()
+	for i := 0; i < 5; i++ {
+		w.Write([]byte("0123456789\n"))
+	}
+	w.Stop()
+
+	for _, name := range foreign {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("foreign file %s removed: %v", name, err)
+		}
+	}
+	backups, _ := filepath.Glob(filepath.Join(dir, "mine.*.log"))
+	if len(backups) > 1 {
+		t.Errorf("backups = %v, want at most 1", backups)
+	}
+}
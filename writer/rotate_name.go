@@ -0,0 +1,167 @@
+package writer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRotateName 切割后文件名的默认格式, 如2026_10_18_15_04_05.log
+const DefaultRotateName = "{date:2006_01_02_15_04_05}.log"
+
+var (
+	hostname, _ = os.Hostname()
+	pid         = strconv.Itoa(os.Getpid())
+)
+
+// rotateName 生成切割后的文件名, 保证不会与目录下已有的文件重名
+type rotateName struct {
+	pattern  string         // 文件名格式
+	location *time.Location // {date}所使用的时区
+	seqKey   string         // 上一次生成文件名时{seq}以外部分的结果
+	seq      int            // 上一次使用的序号
+	ext      string         // 压缩后的扩展名, 压缩后的文件同样不能重名
+	re       *regexp.Regexp // 匹配该格式生成的所有文件名, 包括压缩后的文件
+}
+
+// 返回dir目录下可用的文件名, t为文件中日志的起始时间; 格式中没有{seq}时, 只有在重名时才会在扩展名前添加序号
+func (r *rotateName) next(dir string, t time.Time) string {
+	base := expandName(r.pattern, t.In(r.location))
+	if !strings.Contains(base, "{seq}") {
+		if !r.exists(filepath.Join(dir, base)) {
+			return base
+		}
+		ext := filepath.Ext(base)
+		base = strings.TrimSuffix(base, ext) + ".{seq}" + ext
+	}
+
+	seq := 1
+	if base == r.seqKey {
+		seq = r.seq + 1
+	}
+	for ; ; seq++ {
+		name := strings.Replace(base, "{seq}", fmt.Sprintf("%03d", seq), -1)
+		if !r.exists(filepath.Join(dir, name)) {
+			r.seqKey, r.seq = base, seq
+			return name
+		}
+	}
+}
+
+// 替换{host}、{pid}以及{date}、{date:layout}, {date}的默认格式为2006-01-02
+func expandName(pattern string, t time.Time) string {
+	s := strings.NewReplacer("{host}", hostname, "{pid}", pid).Replace(pattern)
+
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "{date")
+		if i < 0 {
+			break
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			break
+		}
+		verb := s[i+1 : i+end]
+		switch {
+		case verb == "date":
+			b.WriteString(s[:i])
+			b.WriteString(t.Format("2006-01-02"))
+		case strings.HasPrefix(verb, "date:"):
+			b.WriteString(s[:i])
+			b.WriteString(t.Format(verb[len("date:"):]))
+		default:
+			b.WriteString(s[:i+end+1])
+		}
+		s = s[i+end+1:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// 根据文件名格式生成正则表达式: {date}、{seq}、{host}、{pid}匹配任意取值, 格式中没有{seq}时也匹配重名时添加的序号,
+// 保留策略只处理匹配的文件, 避免删除同一目录下其他应用的日志
+func (r *rotateName) compile() {
+	var b strings.Builder
+	b.WriteString("^")
+	pattern := strings.TrimSuffix(r.pattern, ".log")
+	for pattern != "" {
+		i := strings.IndexByte(pattern, '{')
+		end := -1
+		if i >= 0 {
+			end = strings.IndexByte(pattern[i:], '}')
+		}
+		if end < 0 {
+			b.WriteString(regexp.QuoteMeta(pattern))
+			break
+		}
+		b.WriteString(regexp.QuoteMeta(pattern[:i]))
+		switch verb := pattern[i+1 : i+end]; {
+		case verb == "seq":
+			b.WriteString(`\d{3,}`)
+		case verb == "pid":
+			b.WriteString(`\d+`)
+		case verb == "host":
+			b.WriteString(`.+`)
+		case verb == "date":
+			b.WriteString(layoutPattern("2006-01-02"))
+		case strings.HasPrefix(verb, "date:"):
+			b.WriteString(layoutPattern(verb[len("date:"):]))
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+end+1]))
+		}
+		pattern = pattern[i+end+1:]
+	}
+	if !strings.Contains(r.pattern, "{seq}") {
+		b.WriteString(`(\.\d{3,})?`)
+	}
+	b.WriteString(`\.log`)
+	if r.ext != "" {
+		b.WriteString("(" + regexp.QuoteMeta(r.ext) + ")?")
+	}
+	b.WriteString("$")
+	r.re = regexp.MustCompile(b.String())
+}
+
+// 文件名是否可能由该格式生成
+func (r *rotateName) match(name string) bool {
+	return r.re != nil && r.re.MatchString(name)
+}
+
+// 时间格式对应的正则表达式: 连续的数字匹配任意数字, 连续的字母匹配任意字母(月份、星期等)
+func layoutPattern(layout string) string {
+	var b strings.Builder
+	for i := 0; i < len(layout); {
+		c := layout[i]
+		j := i + 1
+		switch {
+		case c >= '0' && c <= '9':
+			for j < len(layout) && layout[j] >= '0' && layout[j] <= '9' {
+				j++
+			}
+			b.WriteString(`\d+`)
+		case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			for j < len(layout) && ((layout[j] >= 'a' && layout[j] <= 'z') || (layout[j] >= 'A' && layout[j] <= 'Z')) {
+				j++
+			}
+			b.WriteString(`[A-Za-z]+`)
+		default:
+			b.WriteString(regexp.QuoteMeta(layout[i:j]))
+		}
+		i = j
+	}
+	return b.String()
+}
+
+func (r *rotateName) exists(path string) bool {
+	return fileExists(path) || (r.ext != "" && fileExists(path+r.ext))
+}
+
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
@@ -0,0 +1,79 @@
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func touch(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRotateNameNext(t *testing.T) {
+	day := time.Date(2026, time.October, 18, 15, 4, 5, 0, testLocation)
+	tests := []struct {
+		name     string
+		pattern  string
+		ext      string
+		existing []string
+		want     []string // 依次生成的文件名, 每次生成后创建该文件
+	}{
+		{"date layout", "{date:2006_01_02_15_04_05}.log", "", nil, []string{"2026_10_18_15_04_05.log", "2026_10_18_15_04_05.001.log", "2026_10_18_15_04_05.002.log"}},
+		{"default date", "app.{date}.{seq}.log", "", nil, []string{"app.2026-10-18.001.log", "app.2026-10-18.002.log"}},
+		{"skip existing seq", "app.{date}.{seq}.log", "", []string{"app.2026-10-18.001.log", "app.2026-10-18.002.log"}, []string{"app.2026-10-18.003.log"}},
+		{"skip compressed", "app.{date}.{seq}.log", ".gz", []string{"app.2026-10-18.001.log.gz"}, []string{"app.2026-10-18.002.log"}},
+		{"pid", "app.{pid}.log", "", nil, []string{"app." + pid + ".log"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			touch(t, dir, tt.existing...)
+			r := &rotateName{pattern: tt.pattern, location: testLocation, ext: tt.ext}
+			for _, want := range tt.want {
+				got := r.next(dir, day)
+				if got != want {
+					t.Fatalf("next() = %q, want %q", got, want)
+				}
+				touch(t, dir, got)
+			}
+		})
+	}
+}
+
+// 到达切割时间时文件为空, 之后写入的日志切割后的文件名应该使用新周期的起始时间
+func TestRotateOnTimeEmptyInterval(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewFileWriter(dir, "temp.log",
+		WithRotateInterval(time.Hour, testLocation),
+		WithRotateName("{date:2006-01-02T15_04_05}.log"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw := w.(*fileWriter)
+	defer fw.file.Close()
+
+	first := time.Date(2026, time.October, 19, 0, 0, 0, 0, testLocation)
+	fw.rotateOnTime(first)
+	if !fw.rotateAt.Equal(first.Add(time.Hour)) {
+		t.Errorf("rotateAt = %v, want %v", fw.rotateAt, first.Add(time.Hour))
+	}
+	fw.writeToFile([]byte("line\n"))
+	fw.rotateOnTime(first.Add(time.Hour))
+
+	if _, err := os.Stat(filepath.Join(dir, "2026-10-19T00_00_00.log")); err != nil {
+		entries, _ := os.ReadDir(dir)
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("rotated file not named after the interval start: %v", names)
+	}
+}
@@ -0,0 +1,70 @@
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var testLocation = time.FixedZone("UTC+8", 8*60*60)
+
+func TestNextRotateTime(t *testing.T) {
+	at := func(day, hour, min, sec int) time.Time {
+		return time.Date(2026, time.October, day, hour, min, sec, 0, testLocation)
+	}
+	tests := []struct {
+		name     string
+		now      time.Time
+		interval time.Duration
+		want     time.Time
+	}{
+		{"hourly", at(18, 10, 30, 0), time.Hour, at(18, 11, 0, 0)},
+		{"hourly on boundary", at(18, 11, 0, 0), time.Hour, at(18, 12, 0, 0)},
+		{"hourly before midnight", at(18, 23, 59, 59), time.Hour, at(19, 0, 0, 0)},
+		{"15 minutes", at(18, 10, 31, 0), 15 * time.Minute, at(18, 10, 45, 0)},
+		{"7h not dividing a day", at(18, 15, 0, 0), 7 * time.Hour, at(18, 21, 0, 0)},
+		{"7h clamped to midnight", at(18, 22, 0, 0), 7 * time.Hour, at(19, 0, 0, 0)},
+		{"daily", at(18, 10, 30, 0), 24 * time.Hour, at(19, 0, 0, 0)},
+		{"daily at midnight", at(18, 0, 0, 0), 24 * time.Hour, at(19, 0, 0, 0)},
+		{"two days", at(18, 10, 30, 0), 48 * time.Hour, at(20, 0, 0, 0)},
+		{"36h truncated to whole days", at(18, 10, 30, 0), 36 * time.Hour, at(19, 0, 0, 0)},
+		{"aligned in loc", time.Date(2026, time.October, 18, 17, 0, 0, 0, time.UTC), 24 * time.Hour, at(20, 0, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextRotateTime(tt.now, tt.interval, testLocation)
+			if !got.Equal(tt.want) {
+				t.Errorf("nextRotateTime(%v, %v) = %v, want %v", tt.now, tt.interval, got, tt.want)
+			}
+		})
+	}
+}
+
+// 重启时从已有文件的最后修改时间计算下一次切割时间, 上一个周期的日志启动后立即切割
+func TestRotateAtFromExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "temp.log")
+	if err := os.WriteFile(path, []byte("line\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-3 * time.Hour)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := NewFileWriter(dir, "temp.log", WithRotateInterval(time.Hour, testLocation))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw := w.(*fileWriter)
+	defer fw.file.Close()
+
+	want := nextRotateTime(mtime, time.Hour, testLocation)
+	if !fw.rotateAt.Equal(want) {
+		t.Errorf("rotateAt = %v, want %v", fw.rotateAt, want)
+	}
+	if !fw.rotateAt.Before(time.Now()) {
+		t.Errorf("rotateAt = %v, want a time in the past", fw.rotateAt)
+	}
+}
@@ -0,0 +1,73 @@
+package writer
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+type stdWriter struct {
+	closed      int32          // writer是否已关闭
+	name        string         // 名称
+	out         io.Writer      // 输出目标
+	wg          sync.WaitGroup // 等待写协程退出
+	done        chan struct{}  // 关闭信号
+	writeBuffer chan []byte    // 写缓存
+}
+
+// NewStdWriter 创建异步输出到out的writer, out通常为os.Stdout或者os.Stderr, 也可以是网络连接等任意io.Writer
+func NewStdWriter(name string, out io.Writer) LogWriter {
+	return &stdWriter{
+		name:        name,
+		out:         out,
+		done:        make(chan struct{}),
+		writeBuffer: make(chan []byte, 1<<10),
+	}
+}
+
+func (s *stdWriter) Name() string {
+	return s.name
+}
+
+func (s *stdWriter) Write(b []byte) (int, error) {
+	if atomic.LoadInt32(&s.closed) == 1 {
+		return 0, ErrClosed
+	}
+	select {
+	case s.writeBuffer <- b:
+		return len(b), nil
+	case <-s.done:
+		return 0, ErrClosed
+	}
+}
+
+func (s *stdWriter) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			select {
+			case <-s.done:
+				return
+			case msg := <-s.writeBuffer:
+				s.out.Write(msg)
+			}
+		}
+	}()
+}
+
+func (s *stdWriter) Stop() {
+	if !atomic.CompareAndSwapInt32(&s.closed, 0, 1) {
+		return
+	}
+	close(s.done)
+	s.wg.Wait()
+	s.clean()
+}
+
+// 写协程退出后, 需要将通道内剩余的日志输出
+func (s *stdWriter) clean() {
+	for len(s.writeBuffer) > 0 {
+		s.out.Write(<-s.writeBuffer)
+	}
+}
@@ -0,0 +1,23 @@
+// Package writer 定义了plogs输出日志的目标(LogWriter)以及内置的终端、文件writer,
+// 自定义writer只需要实现LogWriter接口, 即可通过plogs.WithWriter添加到Logger中
+package writer
+
+import "errors"
+
+var (
+	ErrClosed        = errors.New("writer closed")                  // 向已经Stop的writer写入时返回
+	ErrDuplicateName = errors.New("writer name already registered") // MultipeWriters中已经存在同名的writer
+)
+
+// LogWriter 日志输出目标, Write可能被多个goroutine并发调用,
+// 如果写入可能阻塞, 建议像内置writer一样先放入缓冲区再异步写入
+type LogWriter interface {
+	// Write 写入一条已经编码好的日志, 同一个b可能被多个writer共享, 实现中不能修改b的内容
+	Write(b []byte) (int, error)
+	// Name 返回writer的名称, 同一个Logger中的writer名称不能重复
+	Name() string
+	// Start 在Logger创建完成后调用, 用于启动异步写入等后台任务
+	Start()
+	// Stop 在Logger.Close时调用, 需要将缓冲区中剩余的日志全部写入后再返回
+	Stop()
+}